@@ -0,0 +1,44 @@
+package gerrit
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+)
+
+// AccountsClient is a client that interacts with the Gerrit "accounts" REST API.
+// https://gerrit-review.googlesource.com/Documentation/rest-api-accounts.html
+type AccountsClient struct {
+	*Client
+}
+
+// GetAccount retrieves an account.
+// https://gerrit-review.googlesource.com/Documentation/rest-api-accounts.html#get-account
+func (c *AccountsClient) GetAccount(ctx context.Context, accountID string) (*AccountInfo, error) {
+	x := &AccountInfo{}
+	if err := c.Client.Call(ctx, http.MethodGet, "/accounts/"+url.PathEscape(accountID), nil, x); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// Self retrieves the account of the calling user.
+func (c *AccountsClient) Self(ctx context.Context) (*AccountInfo, error) {
+	return c.GetAccount(ctx, "self")
+}
+
+// QueryAccounts queries accounts visible to the caller.  Additional fields can be
+// requested using opts (e.g. DETAILS, ALL_EMAILS).
+// https://gerrit-review.googlesource.com/Documentation/rest-api-accounts.html#query-account
+func (c *AccountsClient) QueryAccounts(ctx context.Context, query string, opts ...string) ([]AccountInfo, error) {
+	v := url.Values{"q": {query}}
+	if len(opts) > 0 {
+		v["o"] = opts
+	}
+
+	var x []AccountInfo
+	if err := c.Client.Call(ctx, http.MethodGet, "/accounts/?"+v.Encode(), nil, &x); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
@@ -3,6 +3,7 @@ package gerrit
 import (
 	"context"
 	"net/http"
+	"net/url"
 )
 
 // The AttentionSetInfo entity contains details of users that are in the attention set.
@@ -13,6 +14,7 @@ type AttentionSetInfo struct {
 	Reason     string      `json:"reason"`      // The reason of for adding or removing the user.
 }
 
+// AttentionSetClient is a client that interacts with the Gerrit attention set REST APIs.
 type AttentionSetClient struct {
 	*Client
 }
@@ -26,3 +28,28 @@ func (c *AttentionSetClient) GetAttentionSet(ctx context.Context, changeID strin
 	}
 	return x, nil
 }
+
+// AttentionSetInput contains information for adding or removing a user from the attention set.
+// https://gerrit-review.googlesource.com/Documentation/rest-api-changes.html#attention-set-input
+type AttentionSetInput struct {
+	User   string `json:"user,omitempty"`   // The ID of the account that should be added to the attention set (ignored when removing).
+	Reason string `json:"reason"`           // The reason for adding or removing the user.
+	Notify string `json:"notify,omitempty"` // Notify handling that defines to whom email notifications should be sent.
+}
+
+// AddToAttentionSet adds a single user to the attention set of a change.
+// https://gerrit-review.googlesource.com/Documentation/rest-api-changes.html#add-to-attention-set
+func (c *AttentionSetClient) AddToAttentionSet(ctx context.Context, changeID string, input *AttentionSetInput) (*AccountInfo, error) {
+	x := &AccountInfo{}
+	if err := c.Client.Call(ctx, http.MethodPost, "/changes/"+changeID+"/attention", input, x); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// RemoveFromAttentionSet removes a single user from the attention set of a change.
+// https://gerrit-review.googlesource.com/Documentation/rest-api-changes.html#remove-from-attention-set
+func (c *AttentionSetClient) RemoveFromAttentionSet(ctx context.Context, changeID, accountID string, input *AttentionSetInput) error {
+	var x interface{}
+	return c.Client.Call(ctx, http.MethodPost, "/changes/"+changeID+"/attention/"+url.PathEscape(accountID)+"/delete", input, &x)
+}
@@ -2,33 +2,163 @@ package gerrit
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
 	"net/url"
+	"strconv"
+	"strings"
+	"time"
 )
 
 // ChangeInfo contains information about a change.
 // https://gerrit-review.googlesource.com/Documentation/rest-api-changes.html#change-info
 type ChangeInfo struct {
-	Project                string                      `json:"project"`
-	ID                     string                      `json:"id"`
-	ChangeID               string                      `json:"change_id"`
-	UnresolvedCommentCount int                         `json:"unresolved_comment_count"`
-	TotalCommentCount      int                         `json:"total_comment_count"`
-	TrackingIDs            []TrackingIDInfo            `json:"tracking_ids"`
-	Messages               []ChangeMessageInfo         `json:"messages"`
-	Subject                string                      `json:"subject"`
-	Branch                 string                      `json:"branch"`
-	Created                Timestamp                   `json:"created"`
-	Updated                Timestamp                   `json:"updated"`
-	Submitted              Timestamp                   `json:"submitted"`
-	Owner                  AccountInfo                 `json:"owner"`
-	Number                 int                         `json:"_number"`
-	Reviewers              map[string][]AccountInfo    `json:"reviewers"`
-	Revisions              map[string]RevisionInfo     `json:"revisions"`
-	AttentionSet           map[string]AttentionSetInfo `json:"attention_set"`
-	Submittable            bool                        `json:"submittable"` // Only set if requested via SUBMITTABLE option.
+	Project                string                        `json:"project"`
+	ID                     string                        `json:"id"`
+	ChangeID               string                        `json:"change_id"`
+	UnresolvedCommentCount int                           `json:"unresolved_comment_count"`
+	TotalCommentCount      int                           `json:"total_comment_count"`
+	TrackingIDs            []TrackingIDInfo              `json:"tracking_ids"`
+	Messages               []ChangeMessageInfo           `json:"messages"`
+	Subject                string                        `json:"subject"`
+	Branch                 string                        `json:"branch"`
+	Topic                  string                        `json:"topic"`
+	Hashtags               []string                      `json:"hashtags"`
+	Created                Timestamp                     `json:"created"`
+	Updated                Timestamp                     `json:"updated"`
+	Submitted              Timestamp                     `json:"submitted"`
+	Owner                  AccountInfo                   `json:"owner"`
+	Number                 int                           `json:"_number"`
+	MoreChanges            bool                          `json:"_more_changes"` // Set on the last change of a query result if there are more results.
+	Reviewers              map[string][]AccountInfo      `json:"reviewers"`
+	Labels                 map[string]LabelInfo          `json:"labels"` // Only set if requested via LABELS or DETAILED_LABELS options.
+	Revisions              map[string]RevisionInfo       `json:"revisions"`
+	AttentionSet           map[string]AttentionSetInfo   `json:"attention_set"`
+	Submittable            bool                          `json:"submittable"`            // Only set if requested via SUBMITTABLE option.
+	Problems               []ProblemInfo                 `json:"problems"`               // Only set if requested via CHECK option.
+	SubmitRequirements     []SubmitRequirementResultInfo `json:"submit_requirements"`    // Only set if requested via SUBMIT_REQUIREMENTS option.
+	Actions                map[string]ActionInfo         `json:"actions"`                // Only set if requested via CHANGE_ACTIONS or CURRENT_ACTIONS options.
+	ContainsGitConflicts   bool                          `json:"contains_git_conflicts"` // Only set if the change was created with conflicts (e.g. a cherry-pick with AllowConflicts).
+	PermittedLabels        map[string][]string           `json:"permitted_labels"`       // Values the caller is permitted to vote on each label. Only set if requested via DETAILED_LABELS option.
 }
 
+// IsHealthy returns false if any of the problems reported by the consistency
+// check (see OptionCheck) has status ERROR.
+func (ci *ChangeInfo) IsHealthy() bool {
+	for _, p := range ci.Problems {
+		if p.Status == ProblemStatusError {
+			return false
+		}
+	}
+	return true
+}
+
+// IsBotOwned returns true if the owner of the change has one of the given usernames.
+func (ci *ChangeInfo) IsBotOwned(botUsernames map[string]bool) bool {
+	return botUsernames[ci.Owner.Username]
+}
+
+// IsSelfReviewed returns true if the owner of the change has voted (with a non-zero
+// value) on any label.  Requires the DETAILED_LABELS option.
+func (ci *ChangeInfo) IsSelfReviewed() bool {
+	owner := accountKey(ci.Owner)
+	for _, l := range ci.Labels {
+		for _, a := range l.All {
+			if a.Value != 0 && accountKey(a.AccountInfo) == owner {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// CanVote returns true if the caller is permitted to vote value on label.
+// Requires the DETAILED_LABELS option.
+func (ci *ChangeInfo) CanVote(label string, value int) bool {
+	for _, v := range ci.PermittedLabels[label] {
+		n, err := strconv.Atoi(strings.TrimPrefix(strings.TrimSpace(v), "+"))
+		if err == nil && n == value {
+			return true
+		}
+	}
+	return false
+}
+
+// LabelInfo contains information about a label on a change.
+// https://gerrit-review.googlesource.com/Documentation/rest-api-changes.html#label-info
+type LabelInfo struct {
+	Optional     bool              `json:"optional,omitempty"`      // Whether the label is optional.
+	Approved     *AccountInfo      `json:"approved,omitempty"`      // One user who approved this label on the change.
+	Rejected     *AccountInfo      `json:"rejected,omitempty"`      // One user who rejected this label on the change.
+	Recommended  *AccountInfo      `json:"recommended,omitempty"`   // One user who recommended this label on the change.
+	Disliked     *AccountInfo      `json:"disliked,omitempty"`      // One user who disliked this label on the change.
+	Blocking     bool              `json:"blocking,omitempty"`      // If true, the label blocks submit operation.
+	Value        int               `json:"value,omitempty"`         // The voting value of the user who recommended/disliked this label on the change.
+	DefaultValue int               `json:"default_value,omitempty"` // The default voting value for the label.
+	All          []ApprovalInfo    `json:"all,omitempty"`           // List of all approvals for this label. Only set if requested via DETAILED_LABELS option.
+	Values       map[string]string `json:"values,omitempty"`        // A map of all values that are allowed for this label. Only set if requested via DETAILED_LABELS option.
+}
+
+// ApprovalInfo contains information about an approval from a user for a label on a change.
+// https://gerrit-review.googlesource.com/Documentation/rest-api-changes.html#approval-info
+type ApprovalInfo struct {
+	AccountInfo
+
+	Value int       `json:"value"`          // The vote that the user has given for the label.
+	Date  Timestamp `json:"date,omitempty"` // The time and date describing when the approval was made.
+	Tag   string    `json:"tag,omitempty"`  // Value of the tag field from ReviewInput set while posting the review.
+}
+
+// SubmitRequirementResultInfo describes the result of evaluating a submit requirement on a change.
+// https://gerrit-review.googlesource.com/Documentation/rest-api-changes.html#submit-requirement-result-info
+type SubmitRequirementResultInfo struct {
+	Name                           string                           `json:"name"`                                       // The submit requirement name.
+	Description                    string                           `json:"description,omitempty"`                      // Description of the submit requirement.
+	Status                         string                           `json:"status"`                                     // SATISFIED, UNSATISFIED, OVERRIDDEN, NOT_APPLICABLE, ERROR or FORCED.
+	IsLegacy                       bool                             `json:"is_legacy"`                                  // If true, this submit requirement result was created from a legacy SubmitRecord.
+	ApplicabilityExpressionResult  *SubmitRequirementExpressionInfo `json:"applicability_expression_result,omitempty"`  // Result of the applicability expression.
+	SubmittabilityExpressionResult *SubmitRequirementExpressionInfo `json:"submittability_expression_result,omitempty"` // Result of the submittability expression.
+	OverrideExpressionResult       *SubmitRequirementExpressionInfo `json:"override_expression_result,omitempty"`       // Result of the override expression.
+}
+
+// SubmitRequirementResultInfo.Status values.
+const (
+	SubmitRequirementSatisfied     = "SATISFIED"
+	SubmitRequirementUnsatisfied   = "UNSATISFIED"
+	SubmitRequirementOverridden    = "OVERRIDDEN"
+	SubmitRequirementNotApplicable = "NOT_APPLICABLE"
+	SubmitRequirementError         = "ERROR"
+	SubmitRequirementForced        = "FORCED"
+)
+
+// SubmitRequirementExpressionInfo describes the result of evaluating a single submit requirement expression.
+// https://gerrit-review.googlesource.com/Documentation/rest-api-changes.html#submit-requirement-expression-info
+type SubmitRequirementExpressionInfo struct {
+	Expression   string   `json:"expression,omitempty"`    // The submit requirement expression as a string.
+	Fulfilled    bool     `json:"fulfilled"`               // True if the submit requirement is fulfilled for the change.
+	Status       string   `json:"status"`                  // PASS, FAIL, ERROR or NOT_EVALUATED.
+	PassingAtoms []string `json:"passing_atoms,omitempty"` // Atoms of the expression which are fulfilled.
+	FailingAtoms []string `json:"failing_atoms,omitempty"` // Atoms of the expression which are not fulfilled.
+	ErrorMessage string   `json:"error_message,omitempty"` // If the expression could not be evaluated, the error message.
+}
+
+// ProblemInfo contains information about a change consistency problem.
+// https://gerrit-review.googlesource.com/Documentation/rest-api-changes.html#problem-info
+type ProblemInfo struct {
+	Message string `json:"message"`           // Plaintext message describing the problem with the change.
+	Status  string `json:"status,omitempty"`  // The status of fixing the problem (FIXED, FIX_FAILED).
+	Outcome string `json:"outcome,omitempty"` // If Status is set, an additional plaintext message describing the outcome of the fix.
+}
+
+// ProblemInfo.Status values.
+const (
+	ProblemStatusFixed     = "FIXED"
+	ProblemStatusFixFailed = "FIX_FAILED"
+	ProblemStatusError     = "ERROR"
+)
+
 // RevisionInfo contains information about a revision.
 // https://gerrit-review.googlesource.com/Documentation/rest-api-changes.html#revision-info
 type RevisionInfo struct {
@@ -36,25 +166,84 @@ type RevisionInfo struct {
 	Commit   CommitInfo
 	Created  Timestamp
 	Uploader AccountInfo
+	Actions  map[string]ActionInfo `json:"actions"` // Only set if requested via CURRENT_ACTIONS option (current revision only).
 }
 
 // CommitInfo contains information about a commit.
 // https://gerrit-review.googlesource.com/Documentation/rest-api-changes.html#commit-info
 type CommitInfo struct {
-	Parents []CommitInfo
-	Subject string
-	Message string
+	Commit    string        `json:"commit"` // The commit ID. Not set if included in a RevisionInfo entity that is contained in a map which has the commit ID as key.
+	Parents   []CommitInfo  `json:"parents"`
+	Author    GitPersonInfo `json:"author"`
+	Committer GitPersonInfo `json:"committer"`
+	Subject   string        `json:"subject"`
+	Message   string        `json:"message"`
+	WebLinks  []WebLinkInfo `json:"web_links"` // Only set if links are requested.
+}
+
+// GitPersonInfo contains information about the author/committer of a commit.
+// https://gerrit-review.googlesource.com/Documentation/rest-api-changes.html#git-person-info
+type GitPersonInfo struct {
+	Name  string    `json:"name"`  // The name of the author/committer.
+	Email string    `json:"email"` // The email address of the author/committer.
+	Date  Timestamp `json:"date"`  // The timestamp of when this identity was constructed.
+	TZ    int       `json:"tz"`    // The timezone offset from UTC of when this identity was constructed.
+}
+
+// WebLinkInfo describes a link to an external site.
+// https://gerrit-review.googlesource.com/Documentation/rest-api-changes.html#web-link-info
+type WebLinkInfo struct {
+	Name     string `json:"name"`                // The link name.
+	URL      string `json:"url"`                 // The link URL.
+	ImageURL string `json:"image_url,omitempty"` // URL to the icon of the link.
 }
 
 // ChangeMessageInfo contains information about a message attached to a change.
 // https://gerrit-review.googlesource.com/Documentation/rest-api-changes.html#change-message-info
 type ChangeMessageInfo struct {
-	ID             string       // The ID of the message.
-	Author         *AccountInfo // Author of the message as an AccountInfo entity.
-	RealAuthor     *AccountInfo
-	Date           Timestamp
-	Message        string
-	RevisionNumber int `json:"_revision_number,omitempty"` // Which patchset (if any) generated this message.
+	ID             string       `json:"id"`                         // The ID of the message.
+	Author         *AccountInfo `json:"author,omitempty"`           // Author of the message as an AccountInfo entity.
+	RealAuthor     *AccountInfo `json:"real_author,omitempty"`      // Real author of the message (set if the message was posted on behalf of another user).
+	Date           Timestamp    `json:"date"`                       // The timestamp this message was posted.
+	Message        string       `json:"message"`                    // The text left by the user.
+	Tag            string       `json:"tag,omitempty"`              // Tag of the message (e.g. "autogenerated:gerrit:newPatchSet").
+	RevisionNumber int          `json:"_revision_number,omitempty"` // Which patchset (if any) generated this message.
+}
+
+// IsAutogenerated returns true if the message was generated by Gerrit or a tool,
+// i.e. the message has a tag beginning with "autogenerated:".
+func (m ChangeMessageInfo) IsAutogenerated() bool {
+	return strings.HasPrefix(m.Tag, "autogenerated:")
+}
+
+// GetMessages lists the messages of a change, including detailed account information.
+// https://gerrit-review.googlesource.com/Documentation/rest-api-changes.html#list-change-messages
+func (c *ChangesClient) GetMessages(ctx context.Context, changeID string) ([]ChangeMessageInfo, error) {
+	var x []ChangeMessageInfo
+	if err := c.Client.Call(ctx, http.MethodGet, "/changes/"+changeID+"/messages", nil, &x); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// DeleteChangeMessageInput contains options for deleting a change message.
+// https://gerrit-review.googlesource.com/Documentation/rest-api-changes.html#delete-change-message-input
+type DeleteChangeMessageInput struct {
+	Reason string `json:"reason,omitempty"` // The reason why the change message should be deleted.
+}
+
+// DeleteChangeMessage deletes (redacts) a change message, returning the updated
+// message.  Requires administrator permissions.
+// https://gerrit-review.googlesource.com/Documentation/rest-api-changes.html#delete-change-message
+func (c *ChangesClient) DeleteChangeMessage(ctx context.Context, changeID, messageID string, input *DeleteChangeMessageInput) (*ChangeMessageInfo, error) {
+	if input == nil {
+		input = &DeleteChangeMessageInput{}
+	}
+	x := &ChangeMessageInfo{}
+	if err := c.Client.Call(ctx, http.MethodPost, "/changes/"+changeID+"/messages/"+messageID+"/delete", input, x); err != nil {
+		return nil, err
+	}
+	return x, nil
 }
 
 // TrackingIDInfo describes a reference to an external tracking system.
@@ -70,14 +259,76 @@ type ChangesClient struct {
 	*Client
 }
 
+// ChangeOption is an additional field to request when fetching changes (passed
+// to Gerrit as the "o" query parameter). Values not covered by the constants
+// below can be passed as ChangeOption("NAME") to support newer Gerrit versions.
+// https://gerrit-review.googlesource.com/Documentation/rest-api-changes.html#query-options
+type ChangeOption string
+
+// ChangeOption values.
+const (
+	OptionLabels             ChangeOption = "LABELS"              // Summary of each label required for submit, and approvers that have granted (or rejected) with that label.
+	OptionDetailedLabels     ChangeOption = "DETAILED_LABELS"     // Detailed label information, including numeric values of all existing approvals, recognized label values, values permitted to be set by the current user and reviewers that may be removed.
+	OptionSubmitRequirements ChangeOption = "SUBMIT_REQUIREMENTS" // Submit requirements of the change and their status.
+	OptionCurrentRevision    ChangeOption = "CURRENT_REVISION"    // Describe the current revision (patch set) of the change, including the commit SHA-1 and URLs to fetch from.
+	OptionAllRevisions       ChangeOption = "ALL_REVISIONS"       // Describe all revisions, not just current.
+	OptionDownloadCommands   ChangeOption = "DOWNLOAD_COMMANDS"   // Include the commands field in the FetchInfo for revisions.
+	OptionCurrentCommit      ChangeOption = "CURRENT_COMMIT"      // Parse and output all header fields from the commit object, including message. Only valid when the current revision or all revisions are selected.
+	OptionAllCommits         ChangeOption = "ALL_COMMITS"         // Parse and output all header fields from the output revisions.
+	OptionCurrentFiles       ChangeOption = "CURRENT_FILES"       // List files modified by the commit and magic files, including basic line counts inserted/deleted per file.
+	OptionAllFiles           ChangeOption = "ALL_FILES"           // List files modified by the commit and magic files for all revisions.
+	OptionDetailedAccounts   ChangeOption = "DETAILED_ACCOUNTS"   // Include _account_id, email and username fields when referencing accounts.
+	OptionReviewerUpdates    ChangeOption = "REVIEWER_UPDATES"    // Include updates to reviewers set as ReviewerUpdateInfo entities.
+	OptionMessages           ChangeOption = "MESSAGES"            // Include messages associated with the change.
+	OptionCurrentActions     ChangeOption = "CURRENT_ACTIONS"     // Include information on available actions for the change and its current revision.
+	OptionChangeActions      ChangeOption = "CHANGE_ACTIONS"      // Include information on available change actions for the change.
+	OptionReviewed           ChangeOption = "REVIEWED"            // Include the reviewed field if all of the conditions are met.
+	OptionSkipDiffstat       ChangeOption = "SKIP_DIFFSTAT"       // Skip the insertions and deletions field in ChangeInfo.
+	OptionSubmittable        ChangeOption = "SUBMITTABLE"         // Include the submittable field in ChangeInfo.
+	OptionWebLinks           ChangeOption = "WEB_LINKS"           // Include the web_links field in CommitInfo.
+	OptionCheck              ChangeOption = "CHECK"               // Include potential problems with the change.
+	OptionCommitFooters      ChangeOption = "COMMIT_FOOTERS"      // Include the full commit message with Gerrit-specific commit footers in the RevisionInfo.
+	OptionPushCertificates   ChangeOption = "PUSH_CERTIFICATES"   // Include push certificate information in the RevisionInfo.
+	OptionTrackingIDs        ChangeOption = "TRACKING_IDS"        // Include references to external tracking systems as TrackingIdInfo.
+)
+
+// StringOptions converts raw option names (e.g. from configuration) to
+// ChangeOptions.
+func StringOptions(opts ...string) []ChangeOption {
+	out := make([]ChangeOption, 0, len(opts))
+	for _, o := range opts {
+		out = append(out, ChangeOption(o))
+	}
+	return out
+}
+
+// buildOptionsQuery returns the query string (including the leading ?) for the
+// options (as "o" parameters) and any extra parameters, or "" if there are none.
+func buildOptionsQuery(opts []ChangeOption, extra url.Values) string {
+	v := optionsValues(opts, extra)
+	if len(v) == 0 {
+		return ""
+	}
+	return "?" + v.Encode()
+}
+
+// optionsValues returns query parameters for the change options (o) and the
+// extra values.
+func optionsValues(opts []ChangeOption, extra url.Values) url.Values {
+	v := make(url.Values, len(extra)+1)
+	for k, vs := range extra {
+		v[k] = vs
+	}
+	for _, o := range opts {
+		v.Add("o", string(o))
+	}
+	return v
+}
+
 // GetChange retrieves a change.
 // https://gerrit-review.googlesource.com/Documentation/rest-api-changes.html#get-change
-func (c *ChangesClient) GetChange(ctx context.Context, changeID string, opts ...string) (*ChangeInfo, error) {
-	query := ""
-	if len(opts) > 0 {
-		v := url.Values{"o": opts}
-		query = "?" + v.Encode()
-	}
+func (c *ChangesClient) GetChange(ctx context.Context, changeID string, opts ...ChangeOption) (*ChangeInfo, error) {
+	query := buildOptionsQuery(opts, nil)
 
 	x := &ChangeInfo{}
 	if err := c.Client.Call(ctx, http.MethodGet, "/changes/"+changeID+query, nil, x); err != nil {
@@ -86,14 +337,145 @@ func (c *ChangesClient) GetChange(ctx context.Context, changeID string, opts ...
 	return x, nil
 }
 
+// GetChangeRaw is like GetChange, but takes raw option names.  GetChange took
+// opts ...string before ChangeOption was introduced: callers passing a []string
+// can use GetChangeRaw (or StringOptions) instead.
+func (c *ChangesClient) GetChangeRaw(ctx context.Context, changeID string, opts ...string) (*ChangeInfo, error) {
+	return c.GetChange(ctx, changeID, StringOptions(opts...)...)
+}
+
+// maxQueryLength is the maximum length of the encoded query string accepted by
+// QueryChanges, to avoid URLs which exceed server (or proxy) limits.
+const maxQueryLength = 4096
+
+// numbersPerQuery is the number of changes fetched by each query made by
+// QueryChangesByNumbers.
+const numbersPerQuery = 100
+
+// QueryChanges queries changes visible to the caller, requesting further pages
+// of results until all matching changes have been fetched (see Paginator).
+// Returns an error if the encoded query is too long (see QueryChangesByNumbers
+// for fetching many changes).
+// https://gerrit-review.googlesource.com/Documentation/rest-api-changes.html#list-changes
+func (c *ChangesClient) QueryChanges(ctx context.Context, query string, opts ...ChangeOption) ([]ChangeInfo, error) {
+	v := optionsValues(opts, url.Values{"q": {query}})
+	if n := len(v.Encode()) + 1; n > maxQueryLength {
+		return nil, fmt.Errorf("query too long: %d characters encoded (maximum %d)", n, maxQueryLength)
+	}
+
+	p := &Paginator{
+		Client:    c.Client,
+		Path:      "/changes/",
+		Query:     v,
+		MoreField: MoreChangesField,
+	}
+
+	var x []ChangeInfo
+	err := p.Each(ctx, func(r json.RawMessage) error {
+		var ch ChangeInfo
+		if err := json.Unmarshal(r, &ch); err != nil {
+			return err
+		}
+		x = append(x, ch)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// QueryChangesByNumbers fetches the changes with the given numbers, splitting
+// the numbers into batches to keep each query within URL length limits.
+func (c *ChangesClient) QueryChangesByNumbers(ctx context.Context, numbers []int, opts ...ChangeOption) ([]ChangeInfo, error) {
+	var out []ChangeInfo
+	for len(numbers) > 0 {
+		n := numbersPerQuery
+		if n > len(numbers) {
+			n = len(numbers)
+		}
+
+		terms := make([]string, 0, n)
+		for _, x := range numbers[:n] {
+			terms = append(terms, "change:"+strconv.Itoa(x))
+		}
+		numbers = numbers[n:]
+
+		chs, err := c.QueryChanges(ctx, strings.Join(terms, " OR "), opts...)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, chs...)
+	}
+	return out, nil
+}
+
+// ErrMultipleChanges is returned by GetChangeByCommit when more than one change
+// matches the commit (e.g. the commit was uploaded for review to several branches).
+var ErrMultipleChanges = errors.New("multiple changes match")
+
+// GetChangeByCommit retrieves the change with the given commit SHA.  Returns an
+// error matching ErrNotFound if no change matches, or ErrMultipleChanges if more
+// than one change matches.
+func (c *ChangesClient) GetChangeByCommit(ctx context.Context, commitSHA string, opts ...ChangeOption) (*ChangeInfo, error) {
+	chs, err := c.QueryChanges(ctx, "commit:"+commitSHA, opts...)
+	if err != nil {
+		return nil, err
+	}
+	switch len(chs) {
+	case 0:
+		return nil, fmt.Errorf("no change for commit %v: %w", commitSHA, ErrNotFound)
+	case 1:
+		return &chs[0], nil
+	}
+	return nil, fmt.Errorf("%d changes for commit %v: %w", len(chs), commitSHA, ErrMultipleChanges)
+}
+
+// getChangeUntilInterval is the interval between attempts made by GetChangeUntil.
+const getChangeUntilInterval = 500 * time.Millisecond
+
+// GetChangeUntil retrieves a change, retrying every 500ms until pred returns true
+// for the change or ctx is done (use context.WithTimeout to set a deadline).  This
+// is useful for read-after-write, as Gerrit may briefly return stale data after an
+// update.
+func (c *ChangesClient) GetChangeUntil(ctx context.Context, changeID string, pred func(*ChangeInfo) bool, opts ...ChangeOption) (*ChangeInfo, error) {
+	t := time.NewTicker(getChangeUntilInterval)
+	defer t.Stop()
+
+	for {
+		ch, err := c.GetChange(ctx, changeID, opts...)
+		if err != nil {
+			return nil, err
+		}
+		if pred(ch) {
+			return ch, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-t.C:
+		}
+	}
+}
+
+// GetChangeDetail retrieves a change with labels, detailed labels, detailed accounts,
+// reviewer updates and messages.  Additional options can be given in opts.
+// https://gerrit-review.googlesource.com/Documentation/rest-api-changes.html#get-change-detail
+func (c *ChangesClient) GetChangeDetail(ctx context.Context, changeID string, opts ...ChangeOption) (*ChangeInfo, error) {
+	query := buildOptionsQuery(opts, nil)
+
+	x := &ChangeInfo{}
+	if err := c.Client.Call(ctx, http.MethodGet, "/changes/"+changeID+"/detail"+query, nil, x); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
 // ListChangeComments lists the published comments of all revisions of the change.
 // https://gerrit-review.googlesource.com/Documentation/rest-api-changes.html#list-change-comments
-func (c *ChangesClient) ListChangeComments(ctx context.Context, changeID string, opts ...string) (ChangeComments, error) {
-	query := ""
-	if len(opts) > 0 {
-		v := url.Values{"o": opts}
-		query = "?" + v.Encode()
-	}
+func (c *ChangesClient) ListChangeComments(ctx context.Context, changeID string, opts ...ChangeOption) (ChangeComments, error) {
+	query := buildOptionsQuery(opts, nil)
 
 	var x map[string][]CommentInfo
 	if err := c.Client.Call(ctx, http.MethodGet, "/changes/"+changeID+"/comments"+query, nil, &x); err != nil {
@@ -102,31 +484,256 @@ func (c *ChangesClient) ListChangeComments(ctx context.Context, changeID string,
 	return ChangeComments(x), nil
 }
 
+// ListChangeCommentsRaw is like ListChangeComments, but takes raw option names
+// (see GetChangeRaw).
+func (c *ChangesClient) ListChangeCommentsRaw(ctx context.Context, changeID string, opts ...string) (ChangeComments, error) {
+	return c.ListChangeComments(ctx, changeID, StringOptions(opts...)...)
+}
+
+// MoveChange moves a change to a different destination branch, returning the
+// updated change.  If the change cannot be moved (for instance if moving changes
+// is disabled by the server configuration) then the error matches ErrConflict.
+// https://gerrit-review.googlesource.com/Documentation/rest-api-changes.html#move-change
+func (c *ChangesClient) MoveChange(ctx context.Context, changeID string, input *MoveInput) (*ChangeInfo, error) {
+	x := &ChangeInfo{}
+	if err := c.Client.Call(ctx, http.MethodPost, "/changes/"+changeID+"/move", input, x); err != nil {
+		return nil, wrapStatus(err, http.StatusConflict, "could not move change")
+	}
+	return x, nil
+}
+
+// MoveInput contains information for moving a change to a new branch.
+// https://gerrit-review.googlesource.com/Documentation/rest-api-changes.html#move-input
+type MoveInput struct {
+	DestinationBranch string `json:"destination_branch"` // Destination branch.
+	Message           string `json:"message,omitempty"`  // A message to be posted in this change’s comments.
+}
+
+// CommitMessageInput contains information for changing the commit message of a change.
+// https://gerrit-review.googlesource.com/Documentation/rest-api-changes.html#commit-message-input
+type CommitMessageInput struct {
+	Message string `json:"message"`          // New commit message. The Change-Id footer must match the change.
+	Notify  string `json:"notify,omitempty"` // Notify handling that defines to whom email notifications should be sent after the commit message was updated.
+}
+
+// SetCommitMessage creates a new patch set with a new commit message.  Returns an
+// error matching ErrConflict if the message is rejected (e.g. the Change-Id footer
+// does not match the change).
+// https://gerrit-review.googlesource.com/Documentation/rest-api-changes.html#set-message
+func (c *ChangesClient) SetCommitMessage(ctx context.Context, changeID string, input *CommitMessageInput) error {
+	var x interface{}
+	if err := c.Client.Call(ctx, http.MethodPut, "/changes/"+changeID+"/message", input, &x); err != nil {
+		return wrapStatus(err, http.StatusConflict, "commit message rejected")
+	}
+	return nil
+}
+
+// TopicInput contains information for setting a topic.
+// https://gerrit-review.googlesource.com/Documentation/rest-api-changes.html#topic-input
+type TopicInput struct {
+	Topic string `json:"topic,omitempty"` // The topic. The topic will be deleted if not set.
+}
+
+// GetTopic retrieves the topic of a change.
+// https://gerrit-review.googlesource.com/Documentation/rest-api-changes.html#get-topic
+func (c *ChangesClient) GetTopic(ctx context.Context, changeID string) (string, error) {
+	var x string
+	if err := c.Client.Call(ctx, http.MethodGet, "/changes/"+changeID+"/topic", nil, &x); err != nil {
+		return "", err
+	}
+	return x, nil
+}
+
+// SetTopic sets the topic of a change, returning the new topic.  If topic is empty
+// then the topic is deleted.
+// https://gerrit-review.googlesource.com/Documentation/rest-api-changes.html#set-topic
+func (c *ChangesClient) SetTopic(ctx context.Context, changeID, topic string) (string, error) {
+	if topic == "" {
+		// Gerrit responds with 204 (No Content).
+		var x interface{}
+		return "", c.Client.Call(ctx, http.MethodDelete, "/changes/"+changeID+"/topic", nil, &x)
+	}
+
+	var x string
+	if err := c.Client.Call(ctx, http.MethodPut, "/changes/"+changeID+"/topic", &TopicInput{Topic: topic}, &x); err != nil {
+		return "", err
+	}
+	return x, nil
+}
+
+// Index adds or updates the change in the secondary index.  Requires the
+// Maintain Server capability.
+// https://gerrit-review.googlesource.com/Documentation/rest-api-changes.html#index-change
+func (c *ChangesClient) Index(ctx context.Context, changeID string) error {
+	// Gerrit responds with 204 (No Content).
+	var x interface{}
+	if err := c.Client.Call(ctx, http.MethodPost, "/changes/"+changeID+"/index", nil, &x); err != nil {
+		return wrapStatus(err, http.StatusForbidden, "not permitted to index change")
+	}
+	return nil
+}
+
+// RevertInput contains information for reverting a change.
+// https://gerrit-review.googlesource.com/Documentation/rest-api-changes.html#revert-input
+type RevertInput struct {
+	Message string `json:"message,omitempty"` // Message to be added as review comment to the change when reverting the change.
+	Notify  string `json:"notify,omitempty"`  // Notify handling that defines to whom email notifications should be sent for reverting the change.
+	Topic   string `json:"topic,omitempty"`   // Name of the topic for the revert change.
+}
+
+// Revert creates a change which reverts a merged change, returning the new revert
+// change.  Only merged changes can be reverted: for others the error matches
+// ErrConflict.
+// https://gerrit-review.googlesource.com/Documentation/rest-api-changes.html#revert-change
+func (c *ChangesClient) Revert(ctx context.Context, changeID string, input *RevertInput) (*ChangeInfo, error) {
+	if input == nil {
+		input = &RevertInput{}
+	}
+	x := &ChangeInfo{}
+	if err := c.Client.Call(ctx, http.MethodPost, "/changes/"+changeID+"/revert", input, x); err != nil {
+		return nil, wrapStatus(err, http.StatusConflict, "change cannot be reverted")
+	}
+	return x, nil
+}
+
+// StreamChangeComments is like ListChangeComments, but decodes the response
+// incrementally, calling fn for each comment.  If fn returns an error then
+// decoding stops and the error is returned.
+func (c *ChangesClient) StreamChangeComments(ctx context.Context, changeID string, fn func(path string, c CommentInfo) error) error {
+	response, err := c.Client.open(ctx, http.MethodGet, "/changes/"+changeID+"/comments", "", nil)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if err := readPrefix(response.Body); err != nil {
+		return err
+	}
+
+	dec := json.NewDecoder(response.Body)
+	if err := expectDelim(dec, '{'); err != nil {
+		return err
+	}
+	for dec.More() {
+		t, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		path, ok := t.(string)
+		if !ok {
+			return fmt.Errorf("expected path, got %v", t)
+		}
+
+		if err := expectDelim(dec, '['); err != nil {
+			return err
+		}
+		for dec.More() {
+			var ci CommentInfo
+			if err := dec.Decode(&ci); err != nil {
+				return err
+			}
+			if err := fn(path, ci); err != nil {
+				return err
+			}
+		}
+		if err := expectDelim(dec, ']'); err != nil {
+			return err
+		}
+	}
+	return expectDelim(dec, '}')
+}
+
+// expectDelim reads the next token from dec and returns an error if it is not d.
+func expectDelim(dec *json.Decoder, d json.Delim) error {
+	t, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if t != d {
+		return fmt.Errorf("expected %v, got %v", d, t)
+	}
+	return nil
+}
+
 // ChangeComments is a mapping PATH -> CommentInfo.
 type ChangeComments map[string][]CommentInfo
 
+// ReconcileCommentCounts returns the number of unresolved comment threads reported
+// by the server (ci.UnresolvedCommentCount), and the number computed from the
+// comments (threads whose latest comment is unresolved).  These can differ as the
+// count reported by Gerrit is known to drift.
+func ReconcileCommentCounts(ci *ChangeInfo, comments ChangeComments) (serverUnresolved, computedUnresolved int) {
+	repliedTo := make(map[string]bool)
+	for _, cs := range comments {
+		for _, c := range cs {
+			if c.InReplyTo != "" {
+				repliedTo[c.InReplyTo] = true
+			}
+		}
+	}
+
+	for _, cs := range comments {
+		for _, c := range cs {
+			if c.Unresolved && !repliedTo[c.ID] {
+				computedUnresolved++
+			}
+		}
+	}
+	return ci.UnresolvedCommentCount, computedUnresolved
+}
+
 // AccountInfo contains information about an account.
 // https://gerrit-review.googlesource.com/Documentation/rest-api-accounts.html#account-info
 type AccountInfo struct {
-	Name     string
-	Email    string
-	Username string
+	AccountID   int    `json:"_account_id"`            // The numeric ID of the account.
+	Name        string `json:"name"`                   // The full name of the user.
+	DisplayName string `json:"display_name,omitempty"` // The display name of the user.
+	Email       string `json:"email"`                  // The email address the user prefers to be contacted through.
+	Username    string `json:"username"`               // The username of the user.
+	Status      string `json:"status,omitempty"`       // Status message of the account.
+	Inactive    bool   `json:"inactive,omitempty"`     // Whether the account is inactive.
 }
 
 // CommentInfo contains information about a comment.
 // https://gerrit-review.googlesource.com/Documentation/rest-api-changes.html#comment-info
 type CommentInfo struct {
-	ID              string       `json:"id"`
-	Updated         Timestamp    `json:"updated"`
-	PatchSet        int          `json:"patch_set"`
-	Path            string       `json:"path"`
-	Line            int          `json:"line"`
-	Range           CommentRange `json:"range"`
-	ChangeMessageID string       `json:"change_message_id"`
-	Author          AccountInfo  `json:"author"`
-	InReplyTo       string       `json:"in_reply_to"`
-	Message         string       `json:"message"`
-	Unresolved      bool         `json:"unresolved"`
+	ID              string        `json:"id"`
+	Updated         Timestamp     `json:"updated"`
+	PatchSet        int           `json:"patch_set"`
+	Path            string        `json:"path"`
+	Line            int           `json:"line"`
+	Range           *CommentRange `json:"range,omitempty"` // Nil if the comment does not have a range.
+	ChangeMessageID string        `json:"change_message_id"`
+	Author          AccountInfo   `json:"author"`
+	InReplyTo       string        `json:"in_reply_to"`
+	Message         string        `json:"message"`
+	Unresolved      bool          `json:"unresolved"`
+}
+
+// Magic file paths used by Gerrit.
+const (
+	PatchSetLevelPath = "/PATCHSET_LEVEL"
+	CommitMsgPath     = "/COMMIT_MSG"
+)
+
+// URL returns the (relative) URL of the comment in the Gerrit web UI for the change
+// changeID in project.  See CommentURL.
+func (c CommentInfo) URL(project, changeID string) string {
+	return CommentURL("", project, changeID, c.PatchSet, c.Path, c.Line)
+}
+
+// CommentURL returns the URL in the Gerrit web UI at base of a comment at path and
+// line in patchSet of the change changeID in project.  Patchset level comments and
+// comments on a file (rather than a line) have no line anchor.
+func CommentURL(base, project, changeID string, patchSet int, path string, line int) string {
+	base = strings.TrimSuffix(base, "/")
+	if path == "" || path == PatchSetLevelPath {
+		return fmt.Sprintf("%s/c/%s/+/%s/%d", base, project, changeID, patchSet)
+	}
+	if line == 0 {
+		return fmt.Sprintf("%s/c/%s/+/%s/%d/%s", base, project, changeID, patchSet, path)
+	}
+	return fmt.Sprintf("%s/c/%s/+/%s/%d/%s#%d", base, project, changeID, patchSet, path, line)
 }
 
 // CommentRange describes the range of an inline comment.
@@ -140,8 +747,8 @@ type CommentInfo struct {
 // however a range with end_line set to 5 and end_character equal to 0 will
 // not include any characters on line 5,
 type CommentRange struct {
-	StartLine      int // Start line number of the range (1-based).
-	StartCharacter int // Character position in the start line (0-based).
-	EndLine        int // End line number of the range (1-based).
-	EndCharacter   int // Character position in the end line (0-based).
+	StartLine      int `json:"start_line"`      // Start line number of the range (1-based).
+	StartCharacter int `json:"start_character"` // Character position in the start line (0-based).
+	EndLine        int `json:"end_line"`        // End line number of the range (1-based).
+	EndCharacter   int `json:"end_character"`   // Character position in the end line (0-based).
 }
@@ -0,0 +1,324 @@
+package gerrit
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+func TestBuildOptionsQuery(t *testing.T) {
+	tests := []struct {
+		opts  []ChangeOption
+		extra url.Values
+		want  string
+	}{
+		{nil, nil, ""},
+		{[]ChangeOption{OptionLabels}, nil, "?o=LABELS"},
+		{[]ChangeOption{OptionLabels, OptionMessages, OptionCurrentRevision}, nil, "?o=LABELS&o=MESSAGES&o=CURRENT_REVISION"},
+		{nil, url.Values{"q": {"status:open"}}, "?q=status%3Aopen"},
+		{
+			[]ChangeOption{OptionDetailedAccounts},
+			url.Values{"q": {`project:"a/b c" topic:x&y=z#1+2`}},
+			"?o=DETAILED_ACCOUNTS&q=project%3A%22a%2Fb+c%22+topic%3Ax%26y%3Dz%231%2B2",
+		},
+	}
+
+	for _, tt := range tests {
+		if got := buildOptionsQuery(tt.opts, tt.extra); got != tt.want {
+			t.Errorf("buildOptionsQuery(%v, %v) = %q, expected %q", tt.opts, tt.extra, got, tt.want)
+		}
+	}
+
+	// The query must decode back to the original values.
+	q := `project:"a/b c" topic:x&y=z#1+2`
+	v, err := url.ParseQuery(buildOptionsQuery([]ChangeOption{OptionLabels}, url.Values{"q": {q}})[1:])
+	if err != nil {
+		t.Fatalf("url.ParseQuery() = %v", err)
+	}
+	if got := v.Get("q"); got != q {
+		t.Errorf("decoded q = %q, expected %q", got, q)
+	}
+
+	// extra must not be modified.
+	extra := url.Values{"q": {"x"}}
+	buildOptionsQuery([]ChangeOption{OptionLabels}, extra)
+	if len(extra) != 1 || len(extra["q"]) != 1 {
+		t.Errorf("extra modified: %v", extra)
+	}
+}
+
+func TestIsHealthy(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want bool
+	}{
+		{"no problems", `{"_number": 1}`, true},
+		{"warning only", `{"_number": 1, "problems": [{"message": "Patch set 1 has no commit"}]}`, true},
+		{"fixed", `{"_number": 1, "problems": [{"message": "Bad ref", "status": "FIXED", "outcome": "Ref updated"}]}`, true},
+		{"error", `{"_number": 1, "problems": [{"message": "Missing commit"}, {"message": "Bad patch set", "status": "ERROR"}]}`, false},
+	}
+
+	for _, tt := range tests {
+		var ci ChangeInfo
+		if err := json.Unmarshal([]byte(tt.body), &ci); err != nil {
+			t.Fatalf("%s: json.Unmarshal() = %v", tt.name, err)
+		}
+		if got := ci.IsHealthy(); got != tt.want {
+			t.Errorf("%s: IsHealthy() = %v, expected %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestIsBotOwned(t *testing.T) {
+	bots := map[string]bool{"ci-bot": true}
+
+	bot := &ChangeInfo{Owner: AccountInfo{AccountID: 1, Username: "ci-bot"}}
+	if !bot.IsBotOwned(bots) {
+		t.Errorf("IsBotOwned() = false for change owned by ci-bot, expected true")
+	}
+	human := &ChangeInfo{Owner: AccountInfo{AccountID: 2, Username: "alice"}}
+	if human.IsBotOwned(bots) {
+		t.Errorf("IsBotOwned() = true for change owned by alice, expected false")
+	}
+}
+
+func TestIsSelfReviewed(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want bool
+	}{
+		{
+			"self reviewed",
+			`{
+				"owner": {"_account_id": 1, "username": "alice"},
+				"labels": {
+					"Code-Review": {"all": [
+						{"_account_id": 2, "username": "bob", "value": 1},
+						{"_account_id": 1, "username": "alice", "value": 2}
+					]}
+				}
+			}`,
+			true,
+		},
+		{
+			"owner with zero vote",
+			`{
+				"owner": {"_account_id": 1, "username": "alice"},
+				"labels": {
+					"Code-Review": {"all": [
+						{"_account_id": 1, "username": "alice", "value": 0},
+						{"_account_id": 2, "username": "bob", "value": 2}
+					]}
+				}
+			}`,
+			false,
+		},
+		{
+			"no labels",
+			`{"owner": {"_account_id": 1, "username": "alice"}}`,
+			false,
+		},
+	}
+
+	for _, tt := range tests {
+		var ci ChangeInfo
+		if err := json.Unmarshal([]byte(tt.body), &ci); err != nil {
+			t.Fatalf("%s: json.Unmarshal() = %v", tt.name, err)
+		}
+		if got := ci.IsSelfReviewed(); got != tt.want {
+			t.Errorf("%s: IsSelfReviewed() = %v, expected %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestSetTopic(t *testing.T) {
+	c, done := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/a/changes/1/topic" {
+			t.Errorf("unexpected request: %v %v", r.Method, r.URL)
+		}
+		switch r.Method {
+		case http.MethodPut:
+			var in TopicInput
+			if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+				t.Errorf("could not decode topic input: %v", err)
+			}
+			writeJSON(w, strconv.Quote(in.Topic))
+		case http.MethodDelete:
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			t.Errorf("unexpected method: %v", r.Method)
+		}
+	})
+	defer done()
+
+	gcc := &ChangesClient{Client: c}
+	got, err := gcc.SetTopic(context.Background(), "1", "release")
+	if err != nil {
+		t.Fatalf("SetTopic(release) = %v", err)
+	}
+	if got != "release" {
+		t.Errorf("SetTopic(release) = %q, expected release", got)
+	}
+
+	got, err = gcc.SetTopic(context.Background(), "1", "")
+	if err != nil {
+		t.Fatalf("SetTopic() to delete = %v", err)
+	}
+	if got != "" {
+		t.Errorf("SetTopic() to delete = %q, expected empty", got)
+	}
+}
+
+func TestAccountInfoUnmarshal(t *testing.T) {
+	// As returned by GET /accounts/self with o=DETAILS.
+	b := []byte(`{
+		"_account_id": 1000096,
+		"name": "John Doe",
+		"display_name": "JD",
+		"email": "john.doe@example.com",
+		"username": "john",
+		"status": "On vacation",
+		"inactive": true,
+		"registered_on": "2015-07-23 07:01:09.296000000"
+	}`)
+
+	var got AccountInfo
+	if err := json.Unmarshal(b, &got); err != nil {
+		t.Fatalf("json.Unmarshal() = %v", err)
+	}
+	want := AccountInfo{
+		AccountID:   1000096,
+		Name:        "John Doe",
+		DisplayName: "JD",
+		Email:       "john.doe@example.com",
+		Username:    "john",
+		Status:      "On vacation",
+		Inactive:    true,
+	}
+	if got != want {
+		t.Errorf("json.Unmarshal() = %+v, expected %+v", got, want)
+	}
+}
+
+func TestReconcileCommentCounts(t *testing.T) {
+	ci := &ChangeInfo{UnresolvedCommentCount: 3}
+	comments := ChangeComments{
+		"a.go": {
+			{ID: "1", Unresolved: true},                  // Replied to by 2.
+			{ID: "2", InReplyTo: "1", Unresolved: false}, // Resolves the thread.
+			{ID: "3", Unresolved: true},
+		},
+		"b.go": {
+			{ID: "4", Unresolved: false},
+		},
+	}
+
+	server, computed := ReconcileCommentCounts(ci, comments)
+	if server != 3 {
+		t.Errorf("serverUnresolved = %d, expected 3", server)
+	}
+	if computed != 1 {
+		t.Errorf("computedUnresolved = %d, expected 1", computed)
+	}
+}
+
+func TestCommentInfoRange(t *testing.T) {
+	var withRange CommentInfo
+	if err := json.Unmarshal([]byte(`{
+		"id": "1",
+		"line": 1,
+		"range": {"start_line": 0, "start_character": 0, "end_line": 1, "end_character": 5}
+	}`), &withRange); err != nil {
+		t.Fatalf("json.Unmarshal() = %v", err)
+	}
+	want := CommentRange{StartLine: 0, StartCharacter: 0, EndLine: 1, EndCharacter: 5}
+	if withRange.Range == nil {
+		t.Fatalf("Range = nil, expected %+v", want)
+	}
+	if *withRange.Range != want {
+		t.Errorf("Range = %+v, expected %+v", *withRange.Range, want)
+	}
+
+	var withoutRange CommentInfo
+	if err := json.Unmarshal([]byte(`{"id": "2", "line": 3}`), &withoutRange); err != nil {
+		t.Fatalf("json.Unmarshal() = %v", err)
+	}
+	if withoutRange.Range != nil {
+		t.Errorf("Range = %+v, expected nil", *withoutRange.Range)
+	}
+}
+
+// streamCommentsJSON is a comments response with several paths.
+const streamCommentsJSON = `{
+	"/PATCHSET_LEVEL": [
+		{"id": "c1", "patch_set": 1, "message": "Looks good overall"}
+	],
+	"a.go": [
+		{"id": "c2", "patch_set": 1, "line": 3, "message": "Typo"},
+		{"id": "c3", "patch_set": 1, "line": 3, "in_reply_to": "c2", "message": "Done"}
+	],
+	"dir/b.go": [
+		{"id": "c4", "patch_set": 2, "line": 10, "message": "Why?"}
+	]
+}`
+
+func TestStreamChangeComments(t *testing.T) {
+	c, done := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/a/changes/1/comments" {
+			t.Errorf("unexpected request: %v", r.URL)
+		}
+		writeJSON(w, streamCommentsJSON)
+	})
+	defer done()
+
+	cc := &ChangesClient{Client: c}
+	got := make(map[string]string)
+	err := cc.StreamChangeComments(context.Background(), "1", func(path string, ci CommentInfo) error {
+		got[ci.ID] = path
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("StreamChangeComments() = %v", err)
+	}
+
+	want := map[string]string{
+		"c1": PatchSetLevelPath,
+		"c2": "a.go",
+		"c3": "a.go",
+		"c4": "dir/b.go",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("StreamChangeComments() paths = %v, expected %v", got, want)
+	}
+}
+
+func TestStreamChangeCommentsStop(t *testing.T) {
+	c, done := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, streamCommentsJSON)
+	})
+	defer done()
+
+	errStop := errors.New("stop")
+	var ids []string
+	cc := &ChangesClient{Client: c}
+	err := cc.StreamChangeComments(context.Background(), "1", func(path string, ci CommentInfo) error {
+		ids = append(ids, ci.ID)
+		if ci.ID == "c2" {
+			return errStop
+		}
+		return nil
+	})
+	if err != errStop {
+		t.Errorf("StreamChangeComments() = %v, expected %v", err, errStop)
+	}
+	if want := []string{"c1", "c2"}; !reflect.DeepEqual(ids, want) {
+		t.Errorf("fn called for %v, expected %v", ids, want)
+	}
+}
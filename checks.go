@@ -4,7 +4,10 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"strings"
 	"time"
+
+	"github.com/dhowden/gerrit/internal/parallel"
 )
 
 // For more details on the checks JSON API:
@@ -57,14 +60,14 @@ type CheckInfo struct {
 
 // CheckInput contains information for creating or updating a check.
 type CheckInput struct {
-	CheckerUUID   string     `json:"checker_uuid,omitempty"`   //	The UUID of the checker. Must be specified for check creation. Optional only if updating a check and referencing the checker using the UUID in the URL.
-	State         CheckState `json:"state,omitempty"`          //	The state as string-serialized form of CheckState
-	Message       string     `json:"message,omitempty"`        //	Short message explaining the check state.
-	URL           string     `json:"url,omitempty"`            //	A fully-qualified URL pointing to the result of the check on the checker’s infrastructure.
-	Started       *Timestamp `json:"started,omitempty"`        //	The timestamp of when the check started processing.
-	Finished      *Timestamp `json:"finished,omitempty"`       //	The timestamp of when the check finished processing.
-	Notify        string     `json:"notify,omitempty"`         //	Notify handling that defines to whom email notifications should be sent when the combined check state changes due to posting this check. Allowed values are NONE, OWNER, OWNER_REVIEWERS and ALL. If not set, the default is ALL if the combined check state is updated to either SUCCESSFUL or NOT_RELEVANT, otherwise the default is OWNER. Regardless of this setting there are no email notifications for posting checks on non-current patch sets.
-	NotifyDetails string     `json:"notify_details,omitempty"` //	Additional information about whom to notify when the combined check state changes due to posting this check as a map of recipient type to NotifyInfo entity. Regardless of this setting there are no email notifications for posting checks on non-current patch sets.
+	CheckerUUID   string                `json:"checker_uuid,omitempty"`   //	The UUID of the checker. Must be specified for check creation. Optional only if updating a check and referencing the checker using the UUID in the URL.
+	State         CheckState            `json:"state,omitempty"`          //	The state as string-serialized form of CheckState
+	Message       string                `json:"message,omitempty"`        //	Short message explaining the check state.
+	URL           string                `json:"url,omitempty"`            //	A fully-qualified URL pointing to the result of the check on the checker’s infrastructure.
+	Started       *Timestamp            `json:"started,omitempty"`        //	The timestamp of when the check started processing.
+	Finished      *Timestamp            `json:"finished,omitempty"`       //	The timestamp of when the check finished processing.
+	Notify        string                `json:"notify,omitempty"`         //	Notify handling that defines to whom email notifications should be sent when the combined check state changes due to posting this check. Allowed values are NONE, OWNER, OWNER_REVIEWERS and ALL. If not set, the default is ALL if the combined check state is updated to either SUCCESSFUL or NOT_RELEVANT, otherwise the default is OWNER. Regardless of this setting there are no email notifications for posting checks on non-current patch sets.
+	NotifyDetails map[string]NotifyInfo `json:"notify_details,omitempty"` //	Additional information about whom to notify when the combined check state changes due to posting this check as a map of recipient type to NotifyInfo entity. Regardless of this setting there are no email notifications for posting checks on non-current patch sets.
 }
 
 // ChecksClient is a client for interating with the Gerrit Checks API.
@@ -80,19 +83,28 @@ type Timestamp time.Time
 
 const timestampLayout = "2006-01-02 15:04:05.999999999"
 
+// MarshalText implements encoding.TextMarshaler.  The zero Timestamp is
+// encoded as an empty string.
 func (ts Timestamp) MarshalText() ([]byte, error) {
+	if time.Time(ts).IsZero() {
+		return []byte{}, nil
+	}
 	b := make([]byte, 0, len(timestampLayout))
 	b = time.Time(ts).UTC().AppendFormat(b, timestampLayout)
 	return b, nil
 }
 
+// UnmarshalText implements encoding.TextUnmarshaler.  An empty string is
+// decoded as the zero Timestamp.
 func (ts *Timestamp) UnmarshalText(b []byte) error {
-	if len(b) != len(timestampLayout) {
-		return fmt.Errorf("unknown date format %q", b)
+	if len(b) == 0 {
+		*ts = Timestamp{}
+		return nil
 	}
+	// The layout accepts any number of fractional second digits (including none).
 	t, err := time.Parse(timestampLayout, string(b))
 	if err != nil {
-		return err
+		return fmt.Errorf("unknown date format %q: %w", b, err)
 	}
 	*ts = Timestamp(t)
 	return nil
@@ -223,3 +235,158 @@ func (c *ChecksClient) Finish(ctx context.Context, uuid string, changeNumber, pa
 	}
 	return c.updateCheck(ctx, changeNumber, patchSetID, req)
 }
+
+// IsTerminal returns true if the state is SUCCESSFUL, FAILED or NOT_RELEVANT.
+func (c CheckState) IsTerminal() bool {
+	switch c {
+	case StateSuccessful, StateFailed, StateNotRelevant:
+		return true
+	}
+	return false
+}
+
+// Get retrieves the check reported by the checker with the given UUID.
+func (c *ChecksClient) Get(ctx context.Context, uuid string, changeNumber, patchSetID int) (CheckInfo, error) {
+	var resp CheckInfo
+	if err := c.Client.Call(ctx, http.MethodGet, c.checkURL(changeNumber, patchSetID)+"/"+uuid, nil, &resp); err != nil {
+		return CheckInfo{}, err
+	}
+	return resp, nil
+}
+
+// Cancel marks the check as FAILED (with the message "Cancelled"), unless it has
+// already reached a terminal state, in which case the current check is returned
+// unchanged.
+//
+// Note: the check is read before it is updated, so this does not guard against
+// concurrent updates.
+func (c *ChecksClient) Cancel(ctx context.Context, uuid string, changeNumber, patchSetID int) (*CheckInfo, error) {
+	ci, err := c.Get(ctx, uuid, changeNumber, patchSetID)
+	if err != nil {
+		return nil, err
+	}
+	if ci.State.IsTerminal() {
+		return &ci, nil
+	}
+
+	finished := Timestamp(time.Now())
+	req := &CheckInput{
+		CheckerUUID: uuid,
+		State:       StateFailed,
+		Message:     "Cancelled",
+		Finished:    &finished,
+	}
+	ci, err = c.updateCheck(ctx, changeNumber, patchSetID, req)
+	if err != nil {
+		return nil, err
+	}
+	return &ci, nil
+}
+
+// checkStatePrecedence is the precedence of each state when combining states.
+var checkStatePrecedence = map[CheckState]int{
+	StateNotRelevant: 0,
+	StateNotStarted:  1,
+	StateSuccessful:  2,
+	StateScheduled:   3,
+	StateRunning:     4,
+	StateFailed:      5,
+}
+
+// CombineCheckStates returns the combined state of the checks, which is the state
+// with the highest precedence: FAILED, RUNNING, SCHEDULED, SUCCESSFUL, NOT_STARTED
+// and then NOT_RELEVANT.  Returns NOT_RELEVANT if there are no checks.
+func CombineCheckStates(checks []CheckInfo) CheckState {
+	combined := StateNotRelevant
+	for _, c := range checks {
+		if checkStatePrecedence[c.State] > checkStatePrecedence[combined] {
+			combined = c.State
+		}
+	}
+	return combined
+}
+
+// defaultWaitForCheckInterval is the interval used by WaitForCheck when none is given.
+const defaultWaitForCheckInterval = 5 * time.Second
+
+// WaitForCheck polls the check reported by the checker with the given UUID every
+// interval (5s if interval <= 0) until it reaches a terminal state (see
+// CheckState.IsTerminal), returning the final check.  Returns ctx.Err() if ctx is
+// done first.
+func (c *ChecksClient) WaitForCheck(ctx context.Context, changeNumber, patchSetID int, checkerUUID string, interval time.Duration) (*CheckInfo, error) {
+	if interval <= 0 {
+		interval = defaultWaitForCheckInterval
+	}
+	t := time.NewTicker(interval)
+	defer t.Stop()
+
+	for {
+		ci, err := c.Get(ctx, checkerUUID, changeNumber, patchSetID)
+		if err != nil {
+			return nil, err
+		}
+		if ci.State.IsTerminal() {
+			return &ci, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-t.C:
+		}
+	}
+}
+
+// CheckTarget identifies a patch set to update a check on.
+type CheckTarget struct {
+	ChangeNumber int
+	PatchSetID   int
+}
+
+// CheckTargetError is an error updating the check on a target.
+type CheckTargetError struct {
+	Target CheckTarget
+	Err    error
+}
+
+func (e CheckTargetError) Error() string {
+	return fmt.Sprintf("change %d patch set %d: %v", e.Target.ChangeNumber, e.Target.PatchSetID, e.Err)
+}
+
+// UpdateManyError is returned by UpdateMany when the check could not be updated on
+// one or more targets.
+type UpdateManyError []CheckTargetError
+
+func (e UpdateManyError) Error() string {
+	msgs := make([]string, 0, len(e))
+	for _, x := range e {
+		msgs = append(msgs, x.Error())
+	}
+	return fmt.Sprintf("could not update %d check(s): %v", len(e), strings.Join(msgs, "; "))
+}
+
+// maxUpdateConcurrency is the maximum number of concurrent requests made by UpdateMany.
+const maxUpdateConcurrency = 8
+
+// UpdateMany posts the check to each of the targets concurrently.  The returned
+// checks are in the same order as targets (with the zero CheckInfo for failed
+// targets).  If any updates fail then the error is an UpdateManyError.
+func (c *ChecksClient) UpdateMany(ctx context.Context, targets []CheckTarget, req *CheckInput) ([]CheckInfo, error) {
+	out := make([]CheckInfo, len(targets))
+	errs := parallel.Do(ctx, len(targets), maxUpdateConcurrency, func(i int) error {
+		var err error
+		out[i], err = c.updateCheck(ctx, targets[i].ChangeNumber, targets[i].PatchSetID, req)
+		return err
+	})
+
+	var merr UpdateManyError
+	for i, err := range errs {
+		if err != nil {
+			merr = append(merr, CheckTargetError{Target: targets[i], Err: err})
+		}
+	}
+	if len(merr) > 0 {
+		return out, merr
+	}
+	return out, nil
+}
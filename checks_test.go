@@ -0,0 +1,236 @@
+package gerrit
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestTimestampMarshalText(t *testing.T) {
+	ts := Timestamp(time.Date(2020, 1, 2, 3, 4, 5, 123000000, time.UTC))
+	b, err := ts.MarshalText()
+	if err != nil {
+		t.Fatalf("MarshalText() = %v", err)
+	}
+	if got, want := string(b), "2020-01-02 03:04:05.123"; got != want {
+		t.Errorf("MarshalText() = %q, expected %q", got, want)
+	}
+
+	b, err = Timestamp{}.MarshalText()
+	if err != nil {
+		t.Fatalf("MarshalText() of zero Timestamp = %v", err)
+	}
+	if len(b) != 0 {
+		t.Errorf("MarshalText() of zero Timestamp = %q, expected empty", b)
+	}
+
+	// Optional timestamps are omitted when nil.
+	b, err = json.Marshal(&CheckInput{CheckerUUID: "test:checker"})
+	if err != nil {
+		t.Fatalf("json.Marshal() = %v", err)
+	}
+	var m map[string]interface{}
+	if err := json.Unmarshal(b, &m); err != nil {
+		t.Fatalf("json.Unmarshal() = %v", err)
+	}
+	for _, k := range []string{"started", "finished"} {
+		if _, ok := m[k]; ok {
+			t.Errorf("json.Marshal() = %s, expected %q to be omitted", b, k)
+		}
+	}
+}
+
+func TestTimestampUnmarshalText(t *testing.T) {
+	var ts Timestamp
+	if err := ts.UnmarshalText([]byte("2020-01-02 03:04:05.123000000")); err != nil {
+		t.Fatalf("UnmarshalText() = %v", err)
+	}
+	if got, want := ts.Time(), time.Date(2020, 1, 2, 3, 4, 5, 123000000, time.UTC); !got.Equal(want) {
+		t.Errorf("UnmarshalText() = %v, expected %v", got, want)
+	}
+
+	if err := ts.UnmarshalText(nil); err != nil {
+		t.Fatalf("UnmarshalText() of empty = %v", err)
+	}
+	if !ts.Time().IsZero() {
+		t.Errorf("UnmarshalText() of empty = %v, expected zero", ts.Time())
+	}
+
+	if err := ts.UnmarshalText([]byte("02/01/2020")); err == nil {
+		t.Errorf("UnmarshalText() of invalid = nil, expected error")
+	}
+
+	// Round trip.
+	want := Timestamp(time.Date(2021, 6, 7, 8, 9, 10, 11, time.UTC))
+	b, err := want.MarshalText()
+	if err != nil {
+		t.Fatalf("MarshalText() = %v", err)
+	}
+	var got Timestamp
+	if err := got.UnmarshalText(b); err != nil {
+		t.Fatalf("UnmarshalText(%q) = %v", b, err)
+	}
+	if !got.Time().Equal(want.Time()) {
+		t.Errorf("round trip = %v, expected %v", got.Time(), want.Time())
+	}
+}
+
+func TestTimestampUnmarshalTextFraction(t *testing.T) {
+	tests := []struct {
+		in   string
+		nsec int
+	}{
+		{"2020-01-02 03:04:05", 0},
+		{"2020-01-02 03:04:05.123", 123000000},
+		{"2020-01-02 03:04:05.123456", 123456000},
+		{"2020-01-02 03:04:05.123456789", 123456789},
+	}
+
+	for _, tt := range tests {
+		var ts Timestamp
+		if err := ts.UnmarshalText([]byte(tt.in)); err != nil {
+			t.Errorf("UnmarshalText(%q) = %v", tt.in, err)
+			continue
+		}
+		want := time.Date(2020, 1, 2, 3, 4, 5, tt.nsec, time.UTC)
+		if got := ts.Time(); !got.Equal(want) {
+			t.Errorf("UnmarshalText(%q) = %v, expected %v", tt.in, got, want)
+		}
+	}
+}
+
+func TestCombineCheckStates(t *testing.T) {
+	tests := []struct {
+		name   string
+		states []CheckState
+		want   CheckState
+	}{
+		{"none", nil, StateNotRelevant},
+		{"not relevant", []CheckState{StateNotRelevant}, StateNotRelevant},
+		{"not started", []CheckState{StateNotRelevant, StateNotStarted}, StateNotStarted},
+		{"successful", []CheckState{StateNotStarted, StateSuccessful, StateNotRelevant}, StateSuccessful},
+		{"scheduled", []CheckState{StateSuccessful, StateScheduled}, StateScheduled},
+		{"running", []CheckState{StateScheduled, StateRunning, StateSuccessful}, StateRunning},
+		{"failed", []CheckState{StateRunning, StateFailed, StateSuccessful}, StateFailed},
+		{"failed first", []CheckState{StateFailed, StateRunning}, StateFailed},
+	}
+
+	for _, tt := range tests {
+		checks := make([]CheckInfo, 0, len(tt.states))
+		for _, s := range tt.states {
+			checks = append(checks, CheckInfo{State: s})
+		}
+		if got := CombineCheckStates(checks); got != tt.want {
+			t.Errorf("%s: CombineCheckStates(%v) = %v, expected %v", tt.name, tt.states, got, tt.want)
+		}
+	}
+}
+
+func TestCancel(t *testing.T) {
+	tests := []struct {
+		state       CheckState
+		wantPost    bool
+		wantState   CheckState
+		wantMessage string
+	}{
+		{StateRunning, true, StateFailed, "Cancelled"},
+		{StateSuccessful, false, StateSuccessful, ""},
+	}
+
+	for _, tt := range tests {
+		var posted *CheckInput
+		c, done := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			switch r.Method + " " + r.URL.Path {
+			case "GET /a/changes/1/revisions/2/checks/test:checker":
+				writeJSON(w, fmt.Sprintf(`{"checker_uuid": "test:checker", "state": %q}`, tt.state))
+
+			case "POST /a/changes/1/revisions/2/checks":
+				posted = &CheckInput{}
+				if err := json.NewDecoder(r.Body).Decode(posted); err != nil {
+					t.Errorf("could not decode check input: %v", err)
+				}
+				writeJSON(w, fmt.Sprintf(`{"checker_uuid": "test:checker", "state": %q, "message": %q}`, posted.State, posted.Message))
+
+			default:
+				t.Errorf("unexpected request: %v %v", r.Method, r.URL)
+				http.NotFound(w, r)
+			}
+		})
+
+		cc := &ChecksClient{Client: c}
+		ci, err := cc.Cancel(context.Background(), "test:checker", 1, 2)
+		done()
+		if err != nil {
+			t.Errorf("%v: Cancel() = %v", tt.state, err)
+			continue
+		}
+		if (posted != nil) != tt.wantPost {
+			t.Errorf("%v: posted update = %v, expected %v", tt.state, posted != nil, tt.wantPost)
+		}
+		if posted != nil && (posted.Finished == nil || posted.Finished.Time().IsZero()) {
+			t.Errorf("%v: posted update has no finished time", tt.state)
+		}
+		if ci.State != tt.wantState || ci.Message != tt.wantMessage {
+			t.Errorf("%v: Cancel() = %v %q, expected %v %q", tt.state, ci.State, ci.Message, tt.wantState, tt.wantMessage)
+		}
+	}
+}
+
+func TestWaitForCheck(t *testing.T) {
+	states := []CheckState{StateScheduled, StateRunning, StateSuccessful}
+	var n int
+	c, done := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/a/changes/1/revisions/2/checks/test:checker" {
+			t.Errorf("unexpected request: %v %v", r.Method, r.URL)
+			http.NotFound(w, r)
+			return
+		}
+		s := states[len(states)-1]
+		if n < len(states) {
+			s = states[n]
+		}
+		n++
+		writeJSON(w, fmt.Sprintf(`{"checker_uuid": "test:checker", "state": %q}`, s))
+	})
+	defer done()
+
+	cc := &ChecksClient{Client: c}
+	ci, err := cc.WaitForCheck(context.Background(), 1, 2, "test:checker", time.Millisecond)
+	if err != nil {
+		t.Fatalf("WaitForCheck() = %v", err)
+	}
+	if ci.State != StateSuccessful {
+		t.Errorf("WaitForCheck() state = %v, expected %v", ci.State, StateSuccessful)
+	}
+	if n != len(states) {
+		t.Errorf("got %d requests, expected %d", n, len(states))
+	}
+}
+
+func TestWaitForCheckCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var n int
+	c, done := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		n++
+		time.AfterFunc(20*time.Millisecond, cancel)
+		writeJSON(w, `{"checker_uuid": "test:checker", "state": "RUNNING"}`)
+	})
+	defer done()
+
+	// A zero interval uses the default, so the wait ends with the cancellation
+	// rather than the next poll.
+	cc := &ChecksClient{Client: c}
+	_, err := cc.WaitForCheck(ctx, 1, 2, "test:checker", 0)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("WaitForCheck() = %v, expected %v", err, context.Canceled)
+	}
+	if n != 1 {
+		t.Errorf("got %d requests, expected 1", n)
+	}
+}
@@ -3,33 +3,147 @@ package gerrit
 
 import (
 	"bytes"
+	"compress/gzip"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"net/http"
+	"net/url"
+	"strconv"
 	"strings"
+	"time"
 )
 
-// NewClient creates a new gerrit client with the given root (no trailing slash)
-// and user/password to use for basic HTTP auth.
+// NewClient creates a new gerrit client with the given root and user/password to
+// use for basic HTTP auth.  The root should be an absolute URL such as
+// "https://gerrit.example.com" (or "https://example.com/gerrit" if Gerrit is not
+// served from the root of the host); any trailing slash is removed.  Use
+// NewValidatedClient to check the root.
 func NewClient(rootPath, user, password string) *Client {
+	return NewClientWithHTTPClient(rootPath, user, password, http.DefaultClient)
+}
+
+// NewValidatedClient is like NewClient, but returns an error if rootPath is not an
+// absolute URL (see NewClient).
+func NewValidatedClient(rootPath, user, password string) (*Client, error) {
+	u, err := url.Parse(rootPath)
+	if err != nil {
+		return nil, fmt.Errorf("invalid root: %w", err)
+	}
+	if !u.IsAbs() || u.Host == "" {
+		return nil, fmt.Errorf("invalid root: must be an absolute URL (e.g. https://gerrit.example.com): %q", rootPath)
+	}
+	if u.RawQuery != "" || u.Fragment != "" {
+		return nil, fmt.Errorf("invalid root: must not include a query or fragment: %q", rootPath)
+	}
+	return NewClient(rootPath, user, password), nil
+}
+
+// NewClientWithHTTPClient is like NewClient, but uses hc to make requests.  Use this
+// to configure TLS (e.g. a custom CA bundle), timeouts, or to share a transport
+// between clients.  If hc is nil then http.DefaultClient is used.
+func NewClientWithHTTPClient(rootPath, user, password string, hc *http.Client) *Client {
+	if hc == nil {
+		hc = http.DefaultClient
+	}
 	return &Client{
-		Client: http.DefaultClient,
-		root:   rootPath,
-		user:   user,
-		pass:   password,
+		Client:    hc,
+		UserAgent: DefaultUserAgent,
+		root:      strings.TrimSuffix(rootPath, "/"),
+		user:      user,
+		pass:      password,
 	}
 }
 
 // Client provides methods for making requests to the Gerrit REST API.
 type Client struct {
 	*http.Client
+
+	// Limiter, if non-nil, is waited on before each request is made.
+	// A *rate.Limiter from golang.org/x/time/rate satisfies this interface.
+	Limiter Limiter
+
+	// UserAgent is sent as the User-Agent header on every request (if non-empty).
+	UserAgent string
+
+	// Timeout, if non-zero, limits the time taken by each call (including any
+	// retries and reading the response).
+	Timeout time.Duration
+
+	// LogRequest, if non-nil, is called after each HTTP request is made (including
+	// retries) with the request, response (nil if err is non-nil), error and the
+	// time taken.  The Authorization header is removed from the request before it
+	// is passed to LogRequest.  The response body must not be read.
+	LogRequest func(req *http.Request, resp *http.Response, err error, dur time.Duration)
+
+	// RequestHeaders, if non-nil, is called with the context of each call and the
+	// returned headers are added to the request (e.g. X-Gerrit-Trace to correlate
+	// calls with server logs).  The Authorization header cannot be set.
+	RequestHeaders func(ctx context.Context) http.Header
+
+	// Anonymous makes unauthenticated calls: requests are made without the /a/
+	// prefix and without credentials.  Only useful for read-only endpoints on
+	// servers which allow anonymous access.
+	Anonymous bool
+
+	// MaxResponseBytes limits the size of response bodies: reading beyond the limit
+	// fails with ErrResponseTooLarge.  If zero, DefaultMaxResponseBytes is used.  If
+	// negative, response bodies are not limited.
+	MaxResponseBytes int64
+
 	root       string
 	user, pass string
 }
 
+// Root returns the root path of the Gerrit server (as passed to NewClient).
+func (c *Client) Root() string { return c.root }
+
+// WebBase returns the base URL of the Gerrit web UI, assuming it is served from
+// the same host as the REST API (any trailing slash or /a suffix on the root is
+// removed).
+func (c *Client) WebBase() string {
+	return strings.TrimSuffix(strings.TrimSuffix(c.root, "/"), "/a")
+}
+
+// Version of the library, included in DefaultUserAgent.
+const Version = "0.1.0"
+
+// DefaultUserAgent is the User-Agent set by NewClient.
+const DefaultUserAgent = "dhowden-gerrit/" + Version
+
+// DefaultMaxResponseBytes is the default limit on the size of response bodies
+// (see Client.MaxResponseBytes).
+const DefaultMaxResponseBytes = 256 << 20
+
+// ErrResponseTooLarge is returned when reading a response body which exceeds the
+// limit set by Client.MaxResponseBytes.
+var ErrResponseTooLarge = errors.New("response too large")
+
+// Limiter limits the rate of requests made by a Client.
+type Limiter interface {
+	// Wait blocks until a request is permitted or ctx is done.
+	Wait(ctx context.Context) error
+}
+
+// maxRetries is the maximum number of times a request is retried after
+// receiving a 429 (Too Many Requests) or 503 (Service Unavailable) response.
+const maxRetries = 3
+
+type credentialsKey struct{}
+
+type credentials struct {
+	user, pass string
+}
+
+// WithCredentials returns a copy of ctx which overrides the user/password used
+// for basic HTTP auth by calls made with it.
+func WithCredentials(ctx context.Context, user, password string) context.Context {
+	return context.WithValue(ctx, credentialsKey{}, credentials{user: user, pass: password})
+}
+
 type emptyReader struct{}
 
 func (emptyReader) Read(p []byte) (n int, err error) { return 0, io.EOF }
@@ -38,56 +152,333 @@ func (emptyReader) Read(p []byte) (n int, err error) { return 0, io.EOF }
 type CallError struct {
 	Err      error
 	Response []byte
+
+	statusCode int
 }
 
 func (c *CallError) Error() string { return c.Err.Error() }
 
-// Call a url using the given method and body.
-func (c *Client) Call(ctx context.Context, method, url string, body, resp interface{}) error {
-	if strings.HasPrefix(url, "/a/") {
-		return fmt.Errorf("invalid url: must not begin with /a/: %q", url)
+// Errors which CallError matches (using errors.Is) depending on the response status.
+var (
+	ErrNotFound = errors.New("not found") // Response status was 404 (Not Found).
+	ErrConflict = errors.New("conflict")  // Response status was 409 (Conflict).
+)
+
+// Is reports whether the CallError matches target, which allows
+// errors.Is(err, ErrNotFound) and errors.Is(err, ErrConflict).
+func (c *CallError) Is(target error) bool {
+	switch target {
+	case ErrNotFound:
+		return c.statusCode == http.StatusNotFound
+	case ErrConflict:
+		return c.statusCode == http.StatusConflict
+	}
+	return false
+}
+
+// wrapStatus returns err wrapped with msg and the message of the response if err
+// is a *CallError with the status code, and otherwise returns err unchanged.
+func wrapStatus(err error, code int, msg string) error {
+	var ce *CallError
+	if errors.As(err, &ce) && ce.StatusCode() == code {
+		return fmt.Errorf("%s: %v: %w", msg, ce.Message(), err)
 	}
-	url = strings.TrimPrefix(url, "/") // remove leading /
+	return err
+}
+
+// StatusCode returns the HTTP status code of the response.
+func (c *CallError) StatusCode() int { return c.statusCode }
+
+// Message returns the (trimmed) error message in the response body.
+func (c *CallError) Message() string { return strings.TrimSpace(string(c.Response)) }
 
-	var r io.Reader = emptyReader{}
+// Call a url using the given method and body.
+func (c *Client) Call(ctx context.Context, method, url string, body, resp interface{}) error {
+	var b []byte
+	contentType := ""
 	if body != nil {
-		b, err := json.Marshal(body)
+		var err error
+		b, err = json.Marshal(body)
 		if err != nil {
 			return err
 		}
-		r = bytes.NewReader(b)
+		contentType = "application/json; charset=UTF-8"
 	}
 
-	req, err := http.NewRequest(method, c.root+"/a/"+url, r)
+	response, err := c.open(ctx, method, url, contentType, b)
 	if err != nil {
-		return fmt.Errorf("could not create request: %w", err)
+		return err
 	}
+	defer response.Body.Close()
 
-	if body != nil {
-		req.Header.Add("Content-Type", "application/json; charset=UTF-8")
+	// Some endpoints respond with 204 (No Content) on success.
+	if response.StatusCode == http.StatusNoContent {
+		return nil
 	}
-	req.SetBasicAuth(c.user, c.pass)
 
-	response, err := c.Client.Do(req)
+	if err := readPrefix(response.Body); err != nil {
+		return err
+	}
+	return json.NewDecoder(response.Body).Decode(resp)
+}
+
+// Get calls the url using the GET method, decoding the response into out.
+func (c *Client) Get(ctx context.Context, url string, out interface{}) error {
+	return c.Call(ctx, http.MethodGet, url, nil, out)
+}
+
+// Post calls the url using the POST method with the JSON-encoded body, decoding the
+// response into out.
+func (c *Client) Post(ctx context.Context, url string, body, out interface{}) error {
+	return c.Call(ctx, http.MethodPost, url, body, out)
+}
+
+// Put calls the url using the PUT method with the JSON-encoded body, decoding the
+// response into out.
+func (c *Client) Put(ctx context.Context, url string, body, out interface{}) error {
+	return c.Call(ctx, http.MethodPut, url, body, out)
+}
+
+// Delete calls the url using the DELETE method, decoding the response (if any)
+// into out.
+func (c *Client) Delete(ctx context.Context, url string, out interface{}) error {
+	return c.Call(ctx, http.MethodDelete, url, nil, out)
+}
+
+// open makes a request to the url using the given method and body (if
+// contentType is non-empty), returning a *CallError if the response status
+// is not 2xx.  The caller must close the response body.
+func (c *Client) open(ctx context.Context, method, url, contentType string, b []byte) (*http.Response, error) {
+	u, err := c.endpointURL(url)
 	if err != nil {
-		return fmt.Errorf("HTTP request failed: %w", err)
+		return nil, err
+	}
+
+	cancel := context.CancelFunc(func() {})
+	if c.Timeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, c.Timeout)
+	}
+
+	response, err := c.do(ctx, method, u, contentType, b)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	if err := decodeBody(response); err != nil {
+		response.Body.Close()
+		cancel()
+		return nil, err
 	}
-	defer response.Body.Close()
 
-	if response.StatusCode != http.StatusOK {
-		responseBody, _ := ioutil.ReadAll(response.Body)
-		return &CallError{
-			Err:      fmt.Errorf("response status != 200 (%v)", response.Status),
-			Response: responseBody,
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		defer cancel()
+		defer response.Body.Close()
+
+		responseBody, _ := ioutil.ReadAll(c.limitBody(response.Body))
+		return nil, &CallError{
+			Err:        fmt.Errorf("response status not 2xx (%v)", response.Status),
+			Response:   responseBody,
+			statusCode: response.StatusCode,
 		}
 	}
 
-	// Remove the prefix at the beginning of each response.
+	response.Body = &cancelBody{ReadCloser: c.limitBody(response.Body), cancel: cancel}
+	return response, nil
+}
+
+// decodeBody replaces the body of the response with a decoding reader if the
+// response is gzip encoded.
+func decodeBody(response *http.Response) error {
+	if response.StatusCode == http.StatusNoContent || !strings.EqualFold(response.Header.Get("Content-Encoding"), "gzip") {
+		return nil
+	}
+	zr, err := gzip.NewReader(response.Body)
+	if err != nil {
+		return fmt.Errorf("could not decode gzip response: %w", err)
+	}
+	response.Body = &gzipBody{Reader: zr, body: response.Body}
+	response.Header.Del("Content-Encoding")
+	response.Header.Del("Content-Length")
+	response.ContentLength = -1
+	response.Uncompressed = true
+	return nil
+}
+
+// gzipBody is a response body which decodes the gzip encoded body.
+type gzipBody struct {
+	*gzip.Reader
+	body io.ReadCloser
+}
+
+func (b *gzipBody) Close() error {
+	b.Reader.Close()
+	return b.body.Close()
+}
+
+// limitBody returns body limited to the MaxResponseBytes of the client.
+func (c *Client) limitBody(body io.ReadCloser) io.ReadCloser {
+	max := c.MaxResponseBytes
+	if max == 0 {
+		max = DefaultMaxResponseBytes
+	}
+	if max < 0 {
+		return body
+	}
+	return &limitedBody{ReadCloser: body, r: io.LimitReader(body, max+1), max: max}
+}
+
+// limitedBody is a response body which returns ErrResponseTooLarge if more than
+// max bytes are read.
+type limitedBody struct {
+	io.ReadCloser
+	r      io.Reader
+	n, max int64
+}
+
+func (b *limitedBody) Read(p []byte) (int, error) {
+	n, err := b.r.Read(p)
+	b.n += int64(n)
+	if b.n > b.max {
+		return n - int(b.n-b.max), ErrResponseTooLarge
+	}
+	return n, err
+}
+
+// endpointURL returns the full URL of the endpoint, which is a path (relative to
+// the REST API root, with or without a leading slash) optionally followed by a
+// query string.  Path segments must already be escaped.  The endpoint must not
+// include the /a/ prefix used for authenticated calls, which is added unless the
+// client is Anonymous.
+func (c *Client) endpointURL(endpoint string) (string, error) {
+	path := strings.TrimLeft(endpoint, "/")
+	if path == "a" || strings.HasPrefix(path, "a/") || strings.HasPrefix(path, "a?") {
+		return "", fmt.Errorf("invalid url: must not begin with /a/: %q", endpoint)
+	}
+
+	prefix := "/a/"
+	if c.Anonymous {
+		prefix = "/"
+	}
+	return c.root + prefix + path, nil
+}
+
+// cancelBody is a response body which cancels the request context when closed.
+type cancelBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
+
+// readPrefix reads and removes the prefix at the beginning of each response.
+func readPrefix(r io.Reader) error {
 	var prefix [5]byte
-	if _, err = io.ReadFull(response.Body, prefix[:]); err != nil || !bytes.Equal(prefix[:], invalidPrefix) {
+	if _, err := io.ReadFull(r, prefix[:]); err != nil || !bytes.Equal(prefix[:], invalidPrefix) {
 		return fmt.Errorf("expected prefix %q, got %q", invalidPrefix, prefix)
 	}
-	return json.NewDecoder(response.Body).Decode(resp)
+	return nil
+}
+
+// do makes the request, waiting on the Limiter (if set) before each attempt and
+// retrying after the delay given by Retry-After on 429 and 503 responses.  If
+// contentType is non-empty then b is sent as the request body (recreated for each
+// attempt).
+func (c *Client) do(ctx context.Context, method, url, contentType string, b []byte) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		if c.Limiter != nil {
+			if err := c.Limiter.Wait(ctx); err != nil {
+				return nil, fmt.Errorf("rate limiter: %w", err)
+			}
+		}
+
+		var r io.Reader = emptyReader{}
+		if contentType != "" {
+			r = bytes.NewReader(b)
+		}
+
+		req, err := http.NewRequestWithContext(ctx, method, url, r)
+		if err != nil {
+			return nil, fmt.Errorf("could not create request: %w", err)
+		}
+
+		if contentType != "" {
+			req.Header.Add("Content-Type", contentType)
+		}
+		// Setting Accept-Encoding stops the transport from transparently decoding
+		// gzip responses, which are instead decoded by open.
+		req.Header.Set("Accept-Encoding", "gzip")
+		if c.UserAgent != "" {
+			req.Header.Set("User-Agent", c.UserAgent)
+		}
+		if c.RequestHeaders != nil {
+			for k, vs := range c.RequestHeaders(ctx) {
+				if http.CanonicalHeaderKey(k) == "Authorization" {
+					continue
+				}
+				for _, v := range vs {
+					req.Header.Add(k, v)
+				}
+			}
+		}
+		if !c.Anonymous {
+			user, pass := c.user, c.pass
+			if cr, ok := ctx.Value(credentialsKey{}).(credentials); ok {
+				user, pass = cr.user, cr.pass
+			}
+			req.SetBasicAuth(user, pass)
+		}
+
+		start := time.Now()
+		response, err := c.Client.Do(req)
+		if c.LogRequest != nil {
+			lreq := req.Clone(ctx)
+			lreq.Header.Del("Authorization")
+			c.LogRequest(lreq, response, err, time.Since(start))
+		}
+		if err != nil {
+			return nil, fmt.Errorf("HTTP request failed: %w", err)
+		}
+
+		if !retryable(response.StatusCode) || attempt >= maxRetries {
+			return response, nil
+		}
+		response.Body.Close()
+
+		t := time.NewTimer(retryAfter(response.Header.Get("Retry-After")))
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return nil, ctx.Err()
+		case <-t.C:
+		}
+	}
+}
+
+// retryable returns true if a request which received a response with the
+// given status code should be retried.
+func retryable(code int) bool {
+	return code == http.StatusTooManyRequests || code == http.StatusServiceUnavailable
+}
+
+// retryAfter parses the value of a Retry-After header, which is either a number
+// of seconds or an HTTP date.  Returns a one second delay if the value is missing
+// or invalid.
+func retryAfter(v string) time.Duration {
+	if n, err := strconv.Atoi(v); err == nil && n >= 0 {
+		return time.Duration(n) * time.Second
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		if d := time.Until(t); d > 0 {
+			return d
+		}
+		return 0
+	}
+	return time.Second
 }
 
 // invalidPrefix is the junk that gerrit spews out first.
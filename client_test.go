@@ -0,0 +1,217 @@
+package gerrit
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// newTestClient returns a client for a server which handles requests with h.
+// The returned func closes the server.
+func newTestClient(t *testing.T, h http.HandlerFunc) (*Client, func()) {
+	t.Helper()
+	srv := httptest.NewServer(h)
+	return NewClient(srv.URL, "user", "pass"), srv.Close
+}
+
+// writeJSON writes the Gerrit JSON response prefix followed by body.
+func writeJSON(w http.ResponseWriter, body string) {
+	fmt.Fprint(w, ")]}'\n"+body)
+}
+
+func TestRetryReusesBody(t *testing.T) {
+	var bodies [][]byte
+	c, done := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		b, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			t.Errorf("could not read request body: %v", err)
+		}
+		bodies = append(bodies, b)
+		if len(bodies) == 1 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		writeJSON(w, `{"labels": {"Code-Review": 1}}`)
+	})
+	defer done()
+
+	rc := &RevisionClient{Client: c}
+	ri := &ReviewInput{
+		Message: "LGTM",
+		Labels:  map[string]int{"Code-Review": 1},
+	}
+	if err := rc.SetReview(context.Background(), "1", "current", ri); err != nil {
+		t.Fatalf("SetReview() = %v", err)
+	}
+	if len(bodies) != 2 {
+		t.Fatalf("got %d requests, expected 2", len(bodies))
+	}
+	if len(bodies[0]) == 0 || !bytes.Equal(bodies[0], bodies[1]) {
+		t.Errorf("retried body differs: first %q, second %q", bodies[0], bodies[1])
+	}
+}
+
+func TestUserAgent(t *testing.T) {
+	var got string
+	c, done := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Get("User-Agent")
+		writeJSON(w, `"3.9.1"`)
+	})
+	defer done()
+
+	if _, err := c.ServerVersion(context.Background()); err != nil {
+		t.Fatalf("ServerVersion() = %v", err)
+	}
+	if want := "dhowden-gerrit/" + Version; got != want {
+		t.Errorf("User-Agent = %q, expected %q", got, want)
+	}
+}
+
+func TestGzipResponse(t *testing.T) {
+	var fixture bytes.Buffer
+	zw := gzip.NewWriter(&fixture)
+	zw.Write([]byte(")]}'\n" + `{"project": "gerrit", "_number": 1, "subject": "Compressed"}`))
+	zw.Close()
+
+	c, done := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Accept-Encoding"); got != "gzip" {
+			t.Errorf("Accept-Encoding = %q, expected gzip", got)
+		}
+		w.Header().Set("Content-Encoding", "gzip")
+		w.Write(fixture.Bytes())
+	})
+	defer done()
+
+	gcc := &ChangesClient{Client: c}
+	ch, err := gcc.GetChange(context.Background(), "1")
+	if err != nil {
+		t.Fatalf("GetChange() = %v", err)
+	}
+	if ch.Number != 1 || ch.Subject != "Compressed" {
+		t.Errorf("GetChange() = %+v, expected change 1 with subject Compressed", ch)
+	}
+}
+
+func TestEndpointURL(t *testing.T) {
+	c := NewClient("https://gerrit.example.com/", "user", "pass")
+	tests := []struct {
+		endpoint string
+		want     string
+	}{
+		{"/changes/1", "https://gerrit.example.com/a/changes/1"},
+		{"changes/1", "https://gerrit.example.com/a/changes/1"},
+		{"/changes/?q=status%3Aopen&o=LABELS", "https://gerrit.example.com/a/changes/?q=status%3Aopen&o=LABELS"},
+		{"/projects/my%2Fproject/branches/", "https://gerrit.example.com/a/projects/my%2Fproject/branches/"},
+		{"/accounts/self", "https://gerrit.example.com/a/accounts/self"},
+		{"/abandoned", "https://gerrit.example.com/a/abandoned"},
+	}
+	for _, tt := range tests {
+		got, err := c.endpointURL(tt.endpoint)
+		if err != nil {
+			t.Errorf("endpointURL(%q) = %v", tt.endpoint, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("endpointURL(%q) = %q, expected %q", tt.endpoint, got, tt.want)
+		}
+	}
+
+	for _, endpoint := range []string{"/a/changes/1", "a/changes/1", "/a", "/a?q=x"} {
+		if got, err := c.endpointURL(endpoint); err == nil {
+			t.Errorf("endpointURL(%q) = %q, expected error", endpoint, got)
+		}
+	}
+
+	c.Anonymous = true
+	if got, err := c.endpointURL("/changes/1"); err != nil || got != "https://gerrit.example.com/changes/1" {
+		t.Errorf("endpointURL() for anonymous client = %q, %v, expected https://gerrit.example.com/changes/1", got, err)
+	}
+}
+
+func TestWithCredentials(t *testing.T) {
+	var users []string
+	c, done := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		user, _, ok := r.BasicAuth()
+		if !ok {
+			t.Errorf("request without basic auth")
+		}
+		users = append(users, user)
+		writeJSON(w, `"3.9.1"`)
+	})
+	defer done()
+
+	ctx := context.Background()
+	if _, err := c.ServerVersion(WithCredentials(ctx, "other", "secret")); err != nil {
+		t.Fatalf("ServerVersion() = %v", err)
+	}
+	if _, err := c.ServerVersion(ctx); err != nil {
+		t.Fatalf("ServerVersion() = %v", err)
+	}
+
+	if want := []string{"other", "user"}; len(users) != 2 || users[0] != want[0] || users[1] != want[1] {
+		t.Errorf("basic auth users = %v, expected %v", users, want)
+	}
+}
+
+func TestLogRequestOmitsCredentials(t *testing.T) {
+	var serverAuth string
+	c, done := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		serverAuth = r.Header.Get("Authorization")
+		writeJSON(w, `"3.9.1"`)
+	})
+	defer done()
+
+	var logged []*http.Request
+	c.LogRequest = func(req *http.Request, resp *http.Response, err error, dur time.Duration) {
+		logged = append(logged, req)
+	}
+	if _, err := c.ServerVersion(context.Background()); err != nil {
+		t.Fatalf("ServerVersion() = %v", err)
+	}
+
+	if serverAuth == "" {
+		t.Errorf("server request has no Authorization header")
+	}
+	if len(logged) != 1 {
+		t.Fatalf("LogRequest called %d times, expected 1", len(logged))
+	}
+	if got := logged[0].Header.Get("Authorization"); got != "" {
+		t.Errorf("logged request Authorization = %q, expected none", got)
+	}
+}
+
+func TestRequestHeadersCannotOverrideAuth(t *testing.T) {
+	var user, pass, trace string
+	var ok bool
+	c, done := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		user, pass, ok = r.BasicAuth()
+		trace = r.Header.Get("X-Trace-Id")
+		writeJSON(w, `"3.9.1"`)
+	})
+	defer done()
+
+	c.RequestHeaders = func(ctx context.Context) http.Header {
+		// Set directly (rather than with Set/Add) to avoid canonicalising the keys.
+		return http.Header{
+			"authorization": {"Bearer hijacked"},
+			"X-Trace-Id":    {"abc123"},
+		}
+	}
+	if _, err := c.ServerVersion(context.Background()); err != nil {
+		t.Fatalf("ServerVersion() = %v", err)
+	}
+
+	if !ok || user != "user" || pass != "pass" {
+		t.Errorf("server basic auth = %q, %q, %v, expected %q, %q, true", user, pass, ok, "user", "pass")
+	}
+	if trace != "abc123" {
+		t.Errorf("X-Trace-Id = %q, expected %q", trace, "abc123")
+	}
+}
@@ -0,0 +1,79 @@
+package gerrit
+
+import "context"
+
+// ServerVersion returns the version of the Gerrit server.
+// https://gerrit-review.googlesource.com/Documentation/rest-api-config.html#get-version
+func (c *Client) ServerVersion(ctx context.Context) (string, error) {
+	var v string
+	if err := c.Get(ctx, "/config/server/version", &v); err != nil {
+		return "", err
+	}
+	return v, nil
+}
+
+// ServerInfo returns information about the Gerrit server configuration.
+// https://gerrit-review.googlesource.com/Documentation/rest-api-config.html#get-info
+func (c *Client) ServerInfo(ctx context.Context) (*ServerInfo, error) {
+	var si ServerInfo
+	if err := c.Get(ctx, "/config/server/info", &si); err != nil {
+		return nil, err
+	}
+	return &si, nil
+}
+
+// ServerInfo contains information about the Gerrit server configuration.
+// https://gerrit-review.googlesource.com/Documentation/rest-api-config.html#server-info
+type ServerInfo struct {
+	Change ChangeConfigInfo `json:"change"` // Information about the configuration from the change section.
+	Gerrit GerritInfo       `json:"gerrit"` // Information about the configuration from the gerrit section.
+	Plugin PluginConfigInfo `json:"plugin"` // Information about Gerrit extensions by plugins.
+}
+
+// ChangeConfigInfo contains information about Gerrit configuration from the change section.
+// https://gerrit-review.googlesource.com/Documentation/rest-api-config.html#change-config-info
+type ChangeConfigInfo struct {
+	AllowBlame                      bool   `json:"allow_blame,omitempty"`                       // Whether blame is allowed.
+	LargeChange                     int    `json:"large_change"`                                // Number of changed lines from which on a change is considered as a large change.
+	ReplyLabel                      string `json:"reply_label"`                                 // Label name for the reply button.
+	ReplyTooltip                    string `json:"reply_tooltip"`                               // Tooltip for the reply button.
+	UpdateDelay                     int    `json:"update_delay"`                                // How often in seconds the web interface should poll for updates to the currently open change.
+	SubmitWholeTopic                bool   `json:"submit_whole_topic,omitempty"`                // Whether changes with the same topic are submitted together.
+	DisablePrivateChanges           bool   `json:"disable_private_changes,omitempty"`           // Whether private changes are disabled.
+	EnableAttentionSet              bool   `json:"enable_attention_set,omitempty"`              // Whether the attention set is enabled.
+	MergeabilityComputationBehavior string `json:"mergeability_computation_behavior,omitempty"` // How mergeability is computed (only reported by Gerrit 3.5 and later).
+}
+
+// GerritInfo contains information about Gerrit configuration from the gerrit section.
+// https://gerrit-review.googlesource.com/Documentation/rest-api-config.html#gerrit-info
+type GerritInfo struct {
+	AllProjects  string `json:"all_projects"`             // Name of the root project.
+	AllUsers     string `json:"all_users"`                // Name of the project in which meta data of all users is stored.
+	DocURL       string `json:"doc_url,omitempty"`        // Custom base URL where Gerrit server documentation is located.
+	ReportBugURL string `json:"report_bug_url,omitempty"` // URL to report bugs.
+	InstanceID   string `json:"instance_id,omitempty"`    // Instance ID of the Gerrit server.
+}
+
+// PluginConfigInfo contains information about Gerrit extensions by plugins.
+// https://gerrit-review.googlesource.com/Documentation/rest-api-config.html#plugin-config-info
+type PluginConfigInfo struct {
+	HasAvatars bool `json:"has_avatars,omitempty"` // Whether an avatar provider is registered.
+}
+
+// DefaultChangeOptions returns a recommended set of options for GetChange based
+// on the capabilities of the server.  SUBMIT_REQUIREMENTS is only included for
+// servers which report mergeability_computation_behavior (introduced along with
+// submit requirements in Gerrit 3.5).
+func DefaultChangeOptions(si *ServerInfo) []ChangeOption {
+	opts := []ChangeOption{
+		OptionLabels,
+		OptionDetailedAccounts,
+		OptionCurrentRevision,
+		OptionCurrentCommit,
+		OptionMessages,
+	}
+	if si != nil && si.Change.MergeabilityComputationBehavior != "" {
+		opts = append(opts, OptionSubmitRequirements)
+	}
+	return opts
+}
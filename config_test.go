@@ -0,0 +1,58 @@
+package gerrit
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestDefaultChangeOptions(t *testing.T) {
+	tests := []struct {
+		name string
+		info string
+		want []ChangeOption
+	}{
+		{
+			"before submit requirements",
+			`{
+				"change": {"large_change": 500, "reply_label": "Reply", "update_delay": 300},
+				"gerrit": {"all_projects": "All-Projects", "all_users": "All-Users"},
+				"plugin": {}
+			}`,
+			[]ChangeOption{OptionLabels, OptionDetailedAccounts, OptionCurrentRevision, OptionCurrentCommit, OptionMessages},
+		},
+		{
+			"with submit requirements",
+			`{
+				"change": {"large_change": 500, "reply_label": "Reply", "update_delay": 300, "mergeability_computation_behavior": "API_REF_UPDATED_AND_CHANGE_REINDEX"},
+				"gerrit": {"all_projects": "All-Projects", "all_users": "All-Users"},
+				"plugin": {"has_avatars": true}
+			}`,
+			[]ChangeOption{OptionLabels, OptionDetailedAccounts, OptionCurrentRevision, OptionCurrentCommit, OptionMessages, OptionSubmitRequirements},
+		},
+	}
+
+	for _, tt := range tests {
+		c, done := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/a/config/server/info" {
+				t.Errorf("unexpected request: %v %v", r.Method, r.URL)
+			}
+			writeJSON(w, tt.info)
+		})
+
+		si, err := c.ServerInfo(context.Background())
+		done()
+		if err != nil {
+			t.Errorf("%s: ServerInfo() = %v", tt.name, err)
+			continue
+		}
+		if got := DefaultChangeOptions(si); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: DefaultChangeOptions() = %v, expected %v", tt.name, got, tt.want)
+		}
+	}
+
+	if got := DefaultChangeOptions(nil); len(got) != 5 {
+		t.Errorf("DefaultChangeOptions(nil) = %v, expected the base options", got)
+	}
+}
@@ -0,0 +1,55 @@
+package gerrit
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// GetDiff retrieves the diff of a file in a revision.  If base is non-zero, the
+// diff is computed against that patch set (rather than the parent commit), which
+// is useful for reviewing only the changes made since an earlier patch set.
+// https://gerrit-review.googlesource.com/Documentation/rest-api-changes.html#get-diff
+func (c *RevisionClient) GetDiff(ctx context.Context, changeID, revisionID, filePath string, base int) (*DiffInfo, error) {
+	query := ""
+	if base > 0 {
+		v := url.Values{"base": {strconv.Itoa(base)}}
+		query = "?" + v.Encode()
+	}
+
+	x := &DiffInfo{}
+	if err := c.Call(ctx, http.MethodGet, fmt.Sprintf("/changes/%v/revisions/%v/files/%v/diff", changeID, revisionID, url.PathEscape(filePath))+query, nil, x); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// DiffInfo contains information about the diff of a file in a revision.
+// https://gerrit-review.googlesource.com/Documentation/rest-api-changes.html#diff-info
+type DiffInfo struct {
+	MetaA      *DiffFileMetaInfo `json:"meta_a,omitempty"` // Meta information about the file on side A. Not set when ChangeType is ADDED.
+	MetaB      *DiffFileMetaInfo `json:"meta_b,omitempty"` // Meta information about the file on side B. Not set when ChangeType is DELETED.
+	ChangeType string            `json:"change_type"`      // The type of change (ADDED, MODIFIED, DELETED, RENAMED, COPIED, REWRITE).
+	DiffHeader []string          `json:"diff_header"`      // A list of strings representing the patch set diff header.
+	Content    []DiffContent     `json:"content"`          // The content differences in the file.
+	Binary     bool              `json:"binary,omitempty"` // Whether the file is binary.
+}
+
+// DiffFileMetaInfo contains meta information about a file diff.
+// https://gerrit-review.googlesource.com/Documentation/rest-api-changes.html#diff-file-meta-info
+type DiffFileMetaInfo struct {
+	Name        string `json:"name"`         // The name of the file.
+	ContentType string `json:"content_type"` // The content type of the file.
+	Lines       int    `json:"lines"`        // The total number of lines in the file.
+}
+
+// DiffContent contains the content differences in a file.
+// https://gerrit-review.googlesource.com/Documentation/rest-api-changes.html#diff-content
+type DiffContent struct {
+	A    []string `json:"a,omitempty"`    // Content only present in the file on side A.
+	B    []string `json:"b,omitempty"`    // Content only present in the file on side B.
+	AB   []string `json:"ab,omitempty"`   // Content in the file on both sides (unchanged).
+	Skip int      `json:"skip,omitempty"` // Count of lines skipped on both sides when the file is too large to include all common lines.
+}
@@ -0,0 +1,52 @@
+package gerrit
+
+import (
+	"context"
+	"net/http"
+	"testing"
+)
+
+func TestGetDiff(t *testing.T) {
+	tests := []struct {
+		base     int
+		wantBase string
+	}{
+		{0, ""},
+		{2, "2"},
+	}
+
+	for _, tt := range tests {
+		c, done := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			if want := "/a/changes/1/revisions/3/files/dir%2Fmain.go/diff"; r.URL.EscapedPath() != want {
+				t.Errorf("path = %q, expected %q", r.URL.EscapedPath(), want)
+			}
+			if got := r.URL.Query().Get("base"); got != tt.wantBase {
+				t.Errorf("base = %q, expected %q", got, tt.wantBase)
+			}
+			writeJSON(w, `{
+				"meta_a": {"name": "dir/main.go", "content_type": "text/x-go", "lines": 2},
+				"meta_b": {"name": "dir/main.go", "content_type": "text/x-go", "lines": 3},
+				"change_type": "MODIFIED",
+				"content": [
+					{"ab": ["package main"]},
+					{"b": ["// Added."]},
+					{"skip": 10}
+				]
+			}`)
+		})
+
+		rc := &RevisionClient{Client: c}
+		d, err := rc.GetDiff(context.Background(), "1", "3", "dir/main.go", tt.base)
+		done()
+		if err != nil {
+			t.Errorf("GetDiff(base %d) = %v", tt.base, err)
+			continue
+		}
+		if d.ChangeType != "MODIFIED" || d.MetaB == nil || d.MetaB.Lines != 3 {
+			t.Errorf("GetDiff(base %d) = %+v", tt.base, d)
+		}
+		if len(d.Content) != 3 || len(d.Content[1].B) != 1 || d.Content[2].Skip != 10 {
+			t.Errorf("GetDiff(base %d) content = %+v", tt.base, d.Content)
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package gerrit
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+)
+
+// GetChangeEdit retrieves the content of a file from the change edit.  Returns an
+// error matching ErrNotFound if the change has no edit.
+// https://gerrit-review.googlesource.com/Documentation/rest-api-changes.html#get-edit-file
+func (c *ChangesClient) GetChangeEdit(ctx context.Context, changeID, filePath string) ([]byte, error) {
+	response, err := c.Client.open(ctx, http.MethodGet, "/changes/"+changeID+"/edit/"+url.PathEscape(filePath), "", nil)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	// Gerrit responds with 204 (No Content) if there is no change edit.
+	if response.StatusCode == http.StatusNoContent {
+		return nil, fmt.Errorf("change has no edit: %w", ErrNotFound)
+	}
+
+	// The content is returned base64 encoded (without the usual prefix).
+	b, err := ioutil.ReadAll(base64.NewDecoder(base64.StdEncoding, response.Body))
+	if err != nil {
+		return nil, fmt.Errorf("could not decode file content: %w", err)
+	}
+	return b, nil
+}
+
+// PutChangeEdit puts the content of a file into the change edit (creating the
+// change edit if it doesn't exist).
+// https://gerrit-review.googlesource.com/Documentation/rest-api-changes.html#put-edit-file
+func (c *ChangesClient) PutChangeEdit(ctx context.Context, changeID, filePath string, content []byte) error {
+	response, err := c.Client.open(ctx, http.MethodPut, "/changes/"+changeID+"/edit/"+url.PathEscape(filePath), "application/octet-stream", content)
+	if err != nil {
+		return err
+	}
+	return response.Body.Close()
+}
+
+// PublishChangeEditInput contains options for the publishing of a change edit.
+// https://gerrit-review.googlesource.com/Documentation/rest-api-changes.html#publish-change-edit-input
+type PublishChangeEditInput struct {
+	Notify string `json:"notify,omitempty"` // Notify handling that defines to whom email notifications should be sent after the change edit is published.
+}
+
+// PublishChangeEdit promotes the change edit to a regular patch set.
+// https://gerrit-review.googlesource.com/Documentation/rest-api-changes.html#publish-edit
+func (c *ChangesClient) PublishChangeEdit(ctx context.Context, changeID string) error {
+	var x interface{}
+	return c.Client.Call(ctx, http.MethodPost, "/changes/"+changeID+"/edit:publish", &PublishChangeEditInput{}, &x)
+}
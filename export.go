@@ -0,0 +1,45 @@
+package gerrit
+
+import (
+	"context"
+	"fmt"
+)
+
+// ChangeExport contains the review state of a change, suitable for archiving.
+type ChangeExport struct {
+	Change   *ChangeInfo         `json:"change"`   // The change, including messages and the current revision.
+	Comments ChangeComments      `json:"comments"` // The published comments on all revisions of the change.
+	Files    map[string]FileInfo `json:"files"`    // The files modified in the current revision.
+}
+
+// Export fetches the change (with messages and current revision), its comments and
+// the files in the current revision.
+func (c *ChangesClient) Export(ctx context.Context, changeID string) (*ChangeExport, error) {
+	ch, err := c.GetChange(ctx, changeID,
+		OptionMessages,
+		OptionDetailedLabels,
+		OptionDetailedAccounts,
+		OptionCurrentRevision,
+		OptionCurrentCommit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("could not get change: %w", err)
+	}
+
+	comments, err := c.ListChangeComments(ctx, changeID)
+	if err != nil {
+		return nil, fmt.Errorf("could not list change comments: %w", err)
+	}
+
+	rc := &RevisionClient{Client: c.Client}
+	files, err := rc.ListFiles(ctx, changeID, "current")
+	if err != nil {
+		return nil, fmt.Errorf("could not list files: %w", err)
+	}
+
+	return &ChangeExport{
+		Change:   ch,
+		Comments: comments,
+		Files:    files,
+	}, nil
+}
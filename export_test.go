@@ -0,0 +1,59 @@
+package gerrit
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+)
+
+func TestExport(t *testing.T) {
+	c, done := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/a/changes/1":
+			writeJSON(w, `{
+				"project": "p",
+				"_number": 1,
+				"subject": "Fix the thing",
+				"messages": [{"id": "m1", "message": "Uploaded patch set 1.", "date": "2020-01-01 00:00:00.000000000"}]
+			}`)
+		case "/a/changes/1/comments":
+			writeJSON(w, `{"main.go": [{"id": "c1", "line": 3, "message": "Typo", "unresolved": true}]}`)
+		case "/a/changes/1/revisions/current/files":
+			writeJSON(w, `{
+				"/COMMIT_MSG": {"status": "A", "lines_inserted": 7, "size_delta": 551, "size": 551},
+				"main.go": {"lines_inserted": 1, "lines_deleted": 1, "size_delta": 0, "size": 120}
+			}`)
+		default:
+			t.Errorf("unexpected request: %v %v", r.Method, r.URL)
+			http.NotFound(w, r)
+		}
+	})
+	defer done()
+
+	gcc := &ChangesClient{Client: c}
+	x, err := gcc.Export(context.Background(), "1")
+	if err != nil {
+		t.Fatalf("Export() = %v", err)
+	}
+
+	// Round trip the export to check it is portable.
+	b, err := json.Marshal(x)
+	if err != nil {
+		t.Fatalf("json.Marshal() = %v", err)
+	}
+	var got ChangeExport
+	if err := json.Unmarshal(b, &got); err != nil {
+		t.Fatalf("json.Unmarshal() = %v", err)
+	}
+
+	if got.Change == nil || got.Change.Number != 1 || len(got.Change.Messages) != 1 {
+		t.Errorf("Change = %+v, expected change 1 with one message", got.Change)
+	}
+	if cs := got.Comments["main.go"]; len(cs) != 1 || cs[0].Message != "Typo" {
+		t.Errorf("Comments = %+v, expected one comment on main.go", got.Comments)
+	}
+	if len(got.Files) != 2 || got.Files["main.go"].Size != 120 {
+		t.Errorf("Files = %+v, expected /COMMIT_MSG and main.go", got.Files)
+	}
+}
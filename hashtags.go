@@ -0,0 +1,74 @@
+package gerrit
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sort"
+)
+
+// HashtagsInput contains information for adding and removing hashtags on a change.
+// https://gerrit-review.googlesource.com/Documentation/rest-api-changes.html#hashtags-input
+type HashtagsInput struct {
+	Add    []string `json:"add,omitempty"`    // The list of hashtags to be added to the change.
+	Remove []string `json:"remove,omitempty"` // The list of hashtags to be removed from the change.
+}
+
+// ErrHashtagsConflict is returned by SetHashtagsIfUnchanged when the hashtags on
+// the change differ from the expected set.
+var ErrHashtagsConflict = errors.New("hashtags on change differ from expected")
+
+// GetHashtags fetches the hashtags associated with a change.
+// https://gerrit-review.googlesource.com/Documentation/rest-api-changes.html#get-hashtags
+func (c *ChangesClient) GetHashtags(ctx context.Context, changeID string) ([]string, error) {
+	var x []string
+	if err := c.Client.Call(ctx, http.MethodGet, "/changes/"+changeID+"/hashtags", nil, &x); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// SetHashtags adds and/or removes hashtags from a change, returning the hashtags
+// on the change after the update.
+// https://gerrit-review.googlesource.com/Documentation/rest-api-changes.html#set-hashtags
+func (c *ChangesClient) SetHashtags(ctx context.Context, changeID string, input *HashtagsInput) ([]string, error) {
+	var x []string
+	if err := c.Client.Call(ctx, http.MethodPost, "/changes/"+changeID+"/hashtags", input, &x); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// SetHashtagsIfUnchanged is like SetHashtags, but first fetches the current hashtags
+// on the change and returns ErrHashtagsConflict (without making any changes) if they
+// differ from expected.  The order of hashtags is not significant.
+//
+// Note: this is an optimistic check, Gerrit does not provide a way to make the
+// read and update atomic.
+func (c *ChangesClient) SetHashtagsIfUnchanged(ctx context.Context, changeID string, expected []string, input *HashtagsInput) ([]string, error) {
+	current, err := c.GetHashtags(ctx, changeID)
+	if err != nil {
+		return nil, err
+	}
+	if !sameStrings(current, expected) {
+		return current, ErrHashtagsConflict
+	}
+	return c.SetHashtags(ctx, changeID, input)
+}
+
+// sameStrings returns true if a and b contain the same strings (ignoring order).
+func sameStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	a = append([]string(nil), a...)
+	b = append([]string(nil), b...)
+	sort.Strings(a)
+	sort.Strings(b)
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
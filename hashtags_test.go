@@ -0,0 +1,51 @@
+package gerrit
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestSetHashtagsIfUnchanged(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected []string
+		wantPost bool
+		want     []string
+		wantErr  error
+	}{
+		{"matching baseline", []string{"b", "a"}, true, []string{"a", "b", "c"}, nil},
+		{"differing baseline", []string{"a"}, false, []string{"a", "b"}, ErrHashtagsConflict},
+	}
+
+	for _, tt := range tests {
+		posted := false
+		c, done := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			switch r.Method + " " + r.URL.Path {
+			case "GET /a/changes/1/hashtags":
+				writeJSON(w, `["a", "b"]`)
+			case "POST /a/changes/1/hashtags":
+				posted = true
+				writeJSON(w, `["a", "b", "c"]`)
+			default:
+				t.Errorf("unexpected request: %v %v", r.Method, r.URL)
+				http.NotFound(w, r)
+			}
+		})
+
+		gcc := &ChangesClient{Client: c}
+		got, err := gcc.SetHashtagsIfUnchanged(context.Background(), "1", tt.expected, &HashtagsInput{Add: []string{"c"}})
+		done()
+		if !errors.Is(err, tt.wantErr) {
+			t.Errorf("%s: SetHashtagsIfUnchanged() error = %v, expected %v", tt.name, err, tt.wantErr)
+		}
+		if posted != tt.wantPost {
+			t.Errorf("%s: posted = %v, expected %v", tt.name, posted, tt.wantPost)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: SetHashtagsIfUnchanged() = %v, expected %v", tt.name, got, tt.want)
+		}
+	}
+}
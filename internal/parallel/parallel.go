@@ -0,0 +1,39 @@
+// Package parallel provides helpers for making calls concurrently.
+package parallel
+
+import (
+	"context"
+	"sync"
+)
+
+// Do calls fn for each index in [0, n), with at most limit calls running
+// concurrently.  Once ctx is done no further calls are started.  Returns the
+// error of each call, with ctx.Err() for calls which were not started.
+func Do(ctx context.Context, n, limit int, fn func(i int) error) []error {
+	errs := make([]error, n)
+	sem := make(chan struct{}, limit)
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if err := ctx.Err(); err != nil {
+			for j := i; j < n; j++ {
+				errs[j] = err
+			}
+			break
+		}
+
+		wg.Add(1)
+		go func(i int) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			errs[i] = fn(i)
+		}(i)
+	}
+	wg.Wait()
+	return errs
+}
@@ -0,0 +1,70 @@
+package gerrit
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/dhowden/gerrit/internal/parallel"
+)
+
+// MergeableInfo contains information about the mergeability of a revision.
+// https://gerrit-review.googlesource.com/Documentation/rest-api-changes.html#mergeable-info
+type MergeableInfo struct {
+	SubmitType    string   `json:"submit_type"`              // Submit type used for this change.
+	Strategy      string   `json:"strategy,omitempty"`       // The strategy of the merge.
+	Mergeable     bool     `json:"mergeable"`                // Whether the change is mergeable.
+	CommitMerged  bool     `json:"commit_merged,omitempty"`  // Whether the commit has already been merged.
+	ContentMerged bool     `json:"content_merged,omitempty"` // Whether the content of the commit has already been merged.
+	Conflicts     []string `json:"conflicts,omitempty"`      // A list of paths with conflicts.
+}
+
+// GetMergeable gets the mergeability of a revision.
+// https://gerrit-review.googlesource.com/Documentation/rest-api-changes.html#get-mergeable
+func (c *RevisionClient) GetMergeable(ctx context.Context, changeID, revisionID string) (*MergeableInfo, error) {
+	x := &MergeableInfo{}
+	if err := c.Call(ctx, http.MethodGet, fmt.Sprintf("/changes/%v/revisions/%v/mergeable", changeID, revisionID), nil, x); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// mergeabilityConcurrency is the maximum number of concurrent mergeability checks
+// made by MergeabilityReport.  Requests are also subject to the Client Limiter.
+const mergeabilityConcurrency = 8
+
+// MergeabilityReport checks the mergeability of the current revision of each open
+// change on the project branch, returning a map of change number to mergeability.
+func (c *ChangesClient) MergeabilityReport(ctx context.Context, project, branch string) (map[int]bool, error) {
+	chs, err := c.QueryChanges(ctx, fmt.Sprintf("project:%q branch:%q status:open", project, branch))
+	if err != nil {
+		return nil, fmt.Errorf("could not query changes: %w", err)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	rc := &RevisionClient{Client: c.Client}
+	mergeable := make([]bool, len(chs))
+	errs := parallel.Do(ctx, len(chs), mergeabilityConcurrency, func(i int) error {
+		mi, err := rc.GetMergeable(ctx, strconv.Itoa(chs[i].Number), "current")
+		if err != nil {
+			cancel()
+			return err
+		}
+		mergeable[i] = mi.Mergeable
+		return nil
+	})
+	for i, err := range errs {
+		if err != nil {
+			return nil, fmt.Errorf("could not get mergeability of change %d: %w", chs[i].Number, err)
+		}
+	}
+
+	out := make(map[int]bool, len(chs))
+	for i, ch := range chs {
+		out[ch.Number] = mergeable[i]
+	}
+	return out, nil
+}
@@ -0,0 +1,128 @@
+package gerrit
+
+import (
+	"regexp"
+	"strings"
+	"time"
+)
+
+// accountKey returns a key identifying the account.
+func accountKey(a AccountInfo) string {
+	if a.Username != "" {
+		return a.Username
+	}
+	if a.Email != "" {
+		return a.Email
+	}
+	return a.Name
+}
+
+// isUploadMessage returns true if the message was generated by the owner
+// uploading a new patch set.
+func isUploadMessage(m ChangeMessageInfo) bool {
+	return strings.HasPrefix(m.Message, "Uploaded patch set")
+}
+
+// ResponseLatency computes, for each reviewer, the time from the owner's last
+// upload message to that reviewer's first subsequent message.  The returned map
+// is keyed by username (or email/name if not set).  Reviewers who have not
+// responded since the last upload are not included.  Messages are assumed to be
+// in chronological order (as returned by Gerrit).
+func ResponseLatency(msgs []ChangeMessageInfo, owner AccountInfo) map[string]time.Duration {
+	ownerKey := accountKey(owner)
+
+	last := -1
+	for i, m := range msgs {
+		if m.Author != nil && accountKey(*m.Author) == ownerKey && isUploadMessage(m) {
+			last = i
+		}
+	}
+
+	out := make(map[string]time.Duration)
+	if last < 0 {
+		return out
+	}
+
+	uploaded := msgs[last].Date.Time()
+	for _, m := range msgs[last+1:] {
+		if m.Author == nil {
+			continue
+		}
+		k := accountKey(*m.Author)
+		if k == ownerKey {
+			continue
+		}
+		if _, ok := out[k]; ok {
+			continue
+		}
+		out[k] = m.Date.Time().Sub(uploaded)
+	}
+	return out
+}
+
+// Kinds of autogenerated change messages returned by ClassifyMessage.
+const (
+	MessageKindUnknown   = ""
+	MessageKindUpload    = "upload"    // A new patch set was uploaded. Details: "patch_set".
+	MessageKindVote      = "vote"      // Votes were added or removed. Details: "patch_set" and "votes", or "label", "value", "account" and "removed".
+	MessageKindRebase    = "rebase"    // A patch set was rebased. Details: "patch_set" (the patch set which was rebased), if known.
+	MessageKindAttention = "attention" // The attention set was updated. Details: "text".
+)
+
+var (
+	uploadMessageRE = regexp.MustCompile(`^Uploaded patch set (\d+)`)
+	rebaseMessageRE = regexp.MustCompile(`(?i)patch set (\d+) was rebased|successfully rebased`)
+	removedVoteRE   = regexp.MustCompile(`^Removed ([\w-]+)([+-]\d+) by (.+?)\.?$`)
+	patchSetVotesRE = regexp.MustCompile(`^Patch Set (\d+):((?: [\w-]+[+-]\d+)+)\s*$`)
+	attentionSetRE  = regexp.MustCompile(`(?i)attention set`)
+)
+
+// firstLine returns the first line of s, with surrounding whitespace removed.
+func firstLine(s string) string {
+	if i := strings.IndexByte(s, '\n'); i >= 0 {
+		s = s[:i]
+	}
+	return strings.TrimSpace(s)
+}
+
+// ClassifyMessage recognises common kinds of autogenerated change messages
+// (patch set uploads, vote changes, rebases and attention set updates), returning
+// the kind (one of the MessageKind constants) along with details extracted from
+// the message.
+func ClassifyMessage(m ChangeMessageInfo) (kind string, detail map[string]string) {
+	line := firstLine(m.Message)
+
+	if x := rebaseMessageRE.FindStringSubmatch(line); x != nil {
+		detail = make(map[string]string)
+		if x[1] != "" {
+			detail["patch_set"] = x[1]
+		}
+		return MessageKindRebase, detail
+	}
+
+	if x := uploadMessageRE.FindStringSubmatch(line); x != nil {
+		return MessageKindUpload, map[string]string{"patch_set": x[1]}
+	}
+
+	if x := removedVoteRE.FindStringSubmatch(line); x != nil {
+		return MessageKindVote, map[string]string{
+			"label":   x[1],
+			"value":   x[2],
+			"account": x[3],
+			"removed": "true",
+		}
+	}
+
+	if x := patchSetVotesRE.FindStringSubmatch(line); x != nil {
+		return MessageKindVote, map[string]string{
+			"patch_set": x[1],
+			"votes":     strings.TrimSpace(x[2]),
+		}
+	}
+
+	if attentionSetRE.MatchString(line) {
+		return MessageKindAttention, map[string]string{"text": line}
+	}
+
+	return MessageKindUnknown, nil
+}
@@ -0,0 +1,92 @@
+package gerrit
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func message(author *AccountInfo, at time.Time, text string) ChangeMessageInfo {
+	return ChangeMessageInfo{Author: author, Date: Timestamp(at), Message: text}
+}
+
+func TestResponseLatency(t *testing.T) {
+	owner := AccountInfo{AccountID: 1, Username: "owner"}
+	alice := AccountInfo{AccountID: 2, Username: "alice"}
+	bob := AccountInfo{AccountID: 3, Username: "bob"}
+
+	t0 := time.Date(2020, 1, 1, 9, 0, 0, 0, time.UTC)
+	msgs := []ChangeMessageInfo{
+		message(&owner, t0, "Uploaded patch set 1."),
+		message(&bob, t0.Add(30*time.Minute), "Patch Set 1: Code-Review-1"),
+		message(&owner, t0.Add(2*time.Hour), "Uploaded patch set 2."),
+		message(&alice, t0.Add(3*time.Hour), "Patch Set 2: Code-Review+1"),
+		message(&owner, t0.Add(4*time.Hour), "Uploaded patch set 3."),
+		message(&owner, t0.Add(4*time.Hour+time.Minute), "Patch Set 3:\n\nDone"),
+		message(&alice, t0.Add(5*time.Hour), "Patch Set 3: Code-Review+1"),
+		message(&bob, t0.Add(7*time.Hour), "Patch Set 3: Code-Review+2"),
+		message(&alice, t0.Add(8*time.Hour), "Patch Set 3:\n\nThanks"),
+	}
+
+	got := ResponseLatency(msgs, owner)
+	want := map[string]time.Duration{
+		"alice": time.Hour,
+		"bob":   3 * time.Hour,
+	}
+	if len(got) != len(want) {
+		t.Errorf("ResponseLatency() = %v, expected %v", got, want)
+	}
+	for k, v := range want {
+		if got[k] != v {
+			t.Errorf("ResponseLatency()[%q] = %v, expected %v", k, got[k], v)
+		}
+	}
+
+	if got := ResponseLatency(msgs[:1], owner); len(got) != 0 {
+		t.Errorf("ResponseLatency() with no responses = %v, expected empty", got)
+	}
+}
+
+func TestClassifyMessage(t *testing.T) {
+	tests := []struct {
+		msg        string
+		wantKind   string
+		wantDetail map[string]string
+	}{
+		{
+			"Uploaded patch set 3: Commit message was updated.",
+			MessageKindUpload,
+			map[string]string{"patch_set": "3"},
+		},
+		{
+			"Patch Set 2: Code-Review+2 Verified+1\n\n(1 comment)",
+			MessageKindVote,
+			map[string]string{"patch_set": "2", "votes": "Code-Review+2 Verified+1"},
+		},
+		{
+			"Removed Code-Review+2 by Alice <alice@example.com>",
+			MessageKindVote,
+			map[string]string{"label": "Code-Review", "value": "+2", "account": "Alice <alice@example.com>", "removed": "true"},
+		},
+		{
+			"Patch Set 4: Patch Set 3 was rebased",
+			MessageKindRebase,
+			map[string]string{"patch_set": "3"},
+		},
+		{
+			"Patch Set 1:\n\nLooks good, thanks!",
+			MessageKindUnknown,
+			nil,
+		},
+	}
+
+	for _, tt := range tests {
+		kind, detail := ClassifyMessage(ChangeMessageInfo{Message: tt.msg, Tag: "autogenerated:gerrit"})
+		if kind != tt.wantKind {
+			t.Errorf("ClassifyMessage(%q) kind = %q, expected %q", tt.msg, kind, tt.wantKind)
+		}
+		if !reflect.DeepEqual(detail, tt.wantDetail) {
+			t.Errorf("ClassifyMessage(%q) detail = %v, expected %v", tt.msg, detail, tt.wantDetail)
+		}
+	}
+}
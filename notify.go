@@ -0,0 +1,23 @@
+package gerrit
+
+// Notify handling values, which define to whom email notifications are sent.
+const (
+	NotifyNone           = "NONE"
+	NotifyOwner          = "OWNER"
+	NotifyOwnerReviewers = "OWNER_REVIEWERS"
+	NotifyAll            = "ALL"
+)
+
+// Recipient types (keys of NotifyDetails maps).
+const (
+	RecipientTo  = "TO"
+	RecipientCC  = "CC"
+	RecipientBCC = "BCC"
+)
+
+// NotifyInfo contains detailed information about who should be notified about an update.
+// Gerrit only supports listing accounts (there is no field for hashtags).
+// https://gerrit-review.googlesource.com/Documentation/rest-api-changes.html#notify-info
+type NotifyInfo struct {
+	Accounts []string `json:"accounts,omitempty"` // A list of account IDs that identify the accounts that should be notified.
+}
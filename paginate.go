@@ -0,0 +1,82 @@
+package gerrit
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// Fields used by Gerrit to mark the last result of a page when more results are
+// available.
+const (
+	MoreChangesField  = "_more_changes"
+	MoreAccountsField = "_more_accounts"
+)
+
+// Paginator iterates through all the results of a Gerrit list endpoint which
+// supports skipping results with the S parameter, and marks the last result of
+// each page with a "_more_*" field (e.g. /changes/ and /accounts/).
+type Paginator struct {
+	Client *Client
+
+	Path      string     // Path of the endpoint (e.g. "/changes/").
+	Query     url.Values // Query parameters for each request. The S parameter is set by the Paginator.
+	MoreField string     // Field which is set on the last result of a page when more results are available (e.g. MoreChangesField).
+}
+
+// Each requests successive pages of results, calling fn with the JSON of each
+// result.  If fn returns an error then iteration stops and the error is returned.
+func (p *Paginator) Each(ctx context.Context, fn func(json.RawMessage) error) error {
+	v := make(url.Values, len(p.Query)+1)
+	for k, vs := range p.Query {
+		v[k] = vs
+	}
+
+	skip := 0
+	for {
+		if skip > 0 {
+			v.Set("S", strconv.Itoa(skip))
+		}
+
+		var page []json.RawMessage
+		if err := p.Client.Call(ctx, http.MethodGet, p.Path+"?"+v.Encode(), nil, &page); err != nil {
+			return err
+		}
+		for _, r := range page {
+			if err := fn(r); err != nil {
+				return err
+			}
+		}
+		if len(page) == 0 {
+			return nil
+		}
+
+		more, err := p.more(page[len(page)-1])
+		if err != nil {
+			return err
+		}
+		if !more {
+			return nil
+		}
+		skip += len(page)
+	}
+}
+
+// more returns true if the result r has the MoreField set.
+func (p *Paginator) more(r json.RawMessage) (bool, error) {
+	var x map[string]json.RawMessage
+	if err := json.Unmarshal(r, &x); err != nil {
+		return false, err
+	}
+	m, ok := x[p.MoreField]
+	if !ok {
+		return false, nil
+	}
+	var more bool
+	if err := json.Unmarshal(m, &more); err != nil {
+		return false, err
+	}
+	return more, nil
+}
@@ -0,0 +1,93 @@
+package gerrit
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+func TestQueryChangesPages(t *testing.T) {
+	tests := []struct {
+		name  string
+		pages []string
+		want  []int
+	}{
+		{
+			name: "two pages",
+			pages: []string{
+				`[{"_number": 1}, {"_number": 2, "_more_changes": true}]`,
+				`[{"_number": 3}]`,
+			},
+			want: []int{1, 2, 3},
+		},
+		{
+			name: "empty page",
+			pages: []string{
+				`[{"_number": 1}, {"_number": 2, "_more_changes": true}]`,
+				`[]`,
+			},
+			want: []int{1, 2},
+		},
+		{
+			name: "no more field",
+			pages: []string{
+				`[{"_number": 1}, {"_number": 2}]`,
+			},
+			want: []int{1, 2},
+		},
+	}
+
+	for _, tt := range tests {
+		var queries []url.Values
+		c, done := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/a/changes/" {
+				t.Errorf("%v: unexpected request: %v", tt.name, r.URL)
+			}
+			n := len(queries)
+			queries = append(queries, r.URL.Query())
+			if n >= len(tt.pages) {
+				t.Errorf("%v: unexpected request for page %d", tt.name, n+1)
+				writeJSON(w, `[]`)
+				return
+			}
+			writeJSON(w, tt.pages[n])
+		})
+
+		cc := &ChangesClient{Client: c}
+		chs, err := cc.QueryChanges(context.Background(), "status:open", OptionLabels)
+		done()
+		if err != nil {
+			t.Errorf("%v: QueryChanges() = %v", tt.name, err)
+			continue
+		}
+
+		var got []int
+		for _, ch := range chs {
+			got = append(got, ch.Number)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%v: QueryChanges() = %v, expected %v", tt.name, got, tt.want)
+		}
+
+		if len(queries) != len(tt.pages) {
+			t.Errorf("%v: got %d requests, expected %d", tt.name, len(queries), len(tt.pages))
+			continue
+		}
+		if _, ok := queries[0]["S"]; ok {
+			t.Errorf("%v: first request has S set: %v", tt.name, queries[0])
+		}
+		for i := 1; i < len(queries); i++ {
+			q := queries[i]
+			// Each page in these tests after the first follows a page of 2 results.
+			if got, want := q.Get("S"), "2"; got != want {
+				t.Errorf("%v: request %d S = %q, expected %q", tt.name, i+1, got, want)
+			}
+			q.Del("S")
+			if !reflect.DeepEqual(q, queries[0]) {
+				t.Errorf("%v: request %d query = %v, expected %v", tt.name, i+1, q, queries[0])
+			}
+		}
+	}
+}
@@ -0,0 +1,33 @@
+package gerrit
+
+import (
+	"context"
+	"net/http"
+)
+
+// PrivateInput contains information for changing the private flag on a change.
+// https://gerrit-review.googlesource.com/Documentation/rest-api-changes.html#private-input
+type PrivateInput struct {
+	Message string `json:"message,omitempty"` // Message describing why the change was marked private (or non-private).
+}
+
+// SetPrivate marks a change as private.
+// https://gerrit-review.googlesource.com/Documentation/rest-api-changes.html#mark-private
+func (c *ChangesClient) SetPrivate(ctx context.Context, changeID string, input *PrivateInput) error {
+	return c.setPrivate(ctx, "/changes/"+changeID+"/private", input)
+}
+
+// UnsetPrivate marks a change as non-private.
+// https://gerrit-review.googlesource.com/Documentation/rest-api-changes.html#unmark-private
+func (c *ChangesClient) UnsetPrivate(ctx context.Context, changeID string, input *PrivateInput) error {
+	return c.setPrivate(ctx, "/changes/"+changeID+"/private.delete", input)
+}
+
+func (c *ChangesClient) setPrivate(ctx context.Context, url string, input *PrivateInput) error {
+	if input == nil {
+		input = &PrivateInput{}
+	}
+	var x interface{}
+	err := c.Client.Call(ctx, http.MethodPost, url, input, &x)
+	return wrapStatus(err, http.StatusForbidden, "not permitted to change private state")
+}
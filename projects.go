@@ -0,0 +1,153 @@
+package gerrit
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// ProjectsClient is a client that interacts with the Gerrit "projects" REST API.
+// https://gerrit-review.googlesource.com/Documentation/rest-api-projects.html
+type ProjectsClient struct {
+	*Client
+}
+
+// ProjectInfo contains information about a project.
+// https://gerrit-review.googlesource.com/Documentation/rest-api-projects.html#project-info
+type ProjectInfo struct {
+	ID          string            `json:"id"`                    // The URL encoded project name.
+	Name        string            `json:"name,omitempty"`        // The name of the project. Not set if returned in a map where the project name is used as map key.
+	Parent      string            `json:"parent,omitempty"`      // The name of the parent project.
+	Description string            `json:"description,omitempty"` // The description of the project.
+	State       string            `json:"state,omitempty"`       // ACTIVE, READ_ONLY or HIDDEN.
+	Branches    map[string]string `json:"branches,omitempty"`    // Map of branch names to HEAD revisions. Only set if branches are requested.
+	WebLinks    []WebLinkInfo     `json:"web_links,omitempty"`   // Links to the project in external sites.
+}
+
+// Options for ListProjects.
+const (
+	ProjectListDescription = "d"   // Include project description.
+	ProjectListTree        = "t"   // Include parent in output (and list projects as a tree).
+	ProjectListAll         = "all" // Include hidden projects.
+)
+
+// ListProjects lists the projects accessible by the caller.  Each option is
+// either a flag (e.g. ProjectListDescription) or a "name=value" pair (e.g.
+// "b=master", "p=prefix", "n=25").
+// https://gerrit-review.googlesource.com/Documentation/rest-api-projects.html#list-projects
+func (c *ProjectsClient) ListProjects(ctx context.Context, opts ...string) (map[string]ProjectInfo, error) {
+	var x map[string]ProjectInfo
+	if err := c.Client.Call(ctx, http.MethodGet, "/projects/"+encodeFlags(opts), nil, &x); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// GetProject retrieves a project.
+// https://gerrit-review.googlesource.com/Documentation/rest-api-projects.html#get-project
+func (c *ProjectsClient) GetProject(ctx context.Context, name string) (*ProjectInfo, error) {
+	x := &ProjectInfo{}
+	if err := c.Client.Call(ctx, http.MethodGet, "/projects/"+url.PathEscape(name), nil, x); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// encodeFlags encodes the options as a query string (including the leading ?).
+// Options without a value are encoded as flags (e.g. "?d" rather than "?d=").
+func encodeFlags(opts []string) string {
+	if len(opts) == 0 {
+		return ""
+	}
+	parts := make([]string, 0, len(opts))
+	for _, o := range opts {
+		if i := strings.IndexByte(o, '='); i >= 0 {
+			parts = append(parts, url.QueryEscape(o[:i])+"="+url.QueryEscape(o[i+1:]))
+			continue
+		}
+		parts = append(parts, url.QueryEscape(o))
+	}
+	return "?" + strings.Join(parts, "&")
+}
+
+// BranchInfo contains information about a branch.
+// https://gerrit-review.googlesource.com/Documentation/rest-api-projects.html#branch-info
+type BranchInfo struct {
+	Ref       string        `json:"ref"`                  // The ref of the branch.
+	Revision  string        `json:"revision"`             // The revision to which the branch points.
+	CanDelete bool          `json:"can_delete,omitempty"` // Whether the calling user can delete this branch.
+	WebLinks  []WebLinkInfo `json:"web_links,omitempty"`  // Links to the branch in external sites.
+}
+
+// BranchInput contains information for the creation of a new branch.
+// https://gerrit-review.googlesource.com/Documentation/rest-api-projects.html#branch-input
+type BranchInput struct {
+	Revision string `json:"revision,omitempty"` // The base revision of the new branch. If not set, HEAD will be used as base revision.
+}
+
+// ListBranches lists the branches of a project.
+// https://gerrit-review.googlesource.com/Documentation/rest-api-projects.html#list-branches
+func (c *ProjectsClient) ListBranches(ctx context.Context, project string) ([]BranchInfo, error) {
+	var x []BranchInfo
+	if err := c.Client.Call(ctx, http.MethodGet, "/projects/"+url.PathEscape(project)+"/branches/", nil, &x); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// CreateBranch creates a new branch.
+// https://gerrit-review.googlesource.com/Documentation/rest-api-projects.html#create-branch
+func (c *ProjectsClient) CreateBranch(ctx context.Context, project, branch string, input *BranchInput) (*BranchInfo, error) {
+	if input == nil {
+		input = &BranchInput{}
+	}
+	x := &BranchInfo{}
+	if err := c.Client.Call(ctx, http.MethodPut, "/projects/"+url.PathEscape(project)+"/branches/"+url.PathEscape(branch), input, x); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// TagInfo contains information about a tag.
+// https://gerrit-review.googlesource.com/Documentation/rest-api-projects.html#tag-info
+type TagInfo struct {
+	Ref       string         `json:"ref"`                  // The ref of the tag.
+	Revision  string         `json:"revision"`             // For lightweight tags, the revision of the commit to which the tag points. For annotated tags, the revision of the tag object.
+	Object    string         `json:"object,omitempty"`     // The revision of the object to which the tag points. Only set for annotated tags.
+	Message   string         `json:"message,omitempty"`    // The tag message. Only set for annotated tags.
+	Tagger    *GitPersonInfo `json:"tagger,omitempty"`     // The tagger. Only set for annotated tags, if present in the tag.
+	Created   Timestamp      `json:"created,omitempty"`    // The timestamp of when the tag was created.
+	CanDelete bool           `json:"can_delete,omitempty"` // Whether the calling user can delete this tag.
+	WebLinks  []WebLinkInfo  `json:"web_links,omitempty"`  // Links to the tag in external sites.
+}
+
+// TagInput contains information for creating a tag.
+// https://gerrit-review.googlesource.com/Documentation/rest-api-projects.html#tag-input
+type TagInput struct {
+	Revision string `json:"revision,omitempty"` // The revision to which the tag should point. If not specified, the project's HEAD will be used.
+	Message  string `json:"message,omitempty"`  // The tag message. When set, the tag will be created as an annotated tag.
+}
+
+// ListTags lists the tags of a project.
+// https://gerrit-review.googlesource.com/Documentation/rest-api-projects.html#list-tags
+func (c *ProjectsClient) ListTags(ctx context.Context, project string) ([]TagInfo, error) {
+	var x []TagInfo
+	if err := c.Client.Call(ctx, http.MethodGet, "/projects/"+url.PathEscape(project)+"/tags/", nil, &x); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// CreateTag creates a new tag.
+// https://gerrit-review.googlesource.com/Documentation/rest-api-projects.html#create-tag
+func (c *ProjectsClient) CreateTag(ctx context.Context, project, tag string, input *TagInput) (*TagInfo, error) {
+	if input == nil {
+		input = &TagInput{}
+	}
+	x := &TagInfo{}
+	if err := c.Client.Call(ctx, http.MethodPut, "/projects/"+url.PathEscape(project)+"/tags/"+url.PathEscape(tag), input, x); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
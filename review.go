@@ -2,8 +2,12 @@ package gerrit
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
 )
 
 // RevisionClient is a client that interacts with the Gerrit "revision" REST APIs.
@@ -12,15 +16,207 @@ type RevisionClient struct {
 	*Client
 }
 
-// SetReview adds a review to a change.
+// SetReview adds a review to a change.  If the review applies a label which the
+// user is not permitted to set then the returned error is a *LabelNotPermittedError.
+// If ri.OnBehalfOf is set and the user lacks permission to review on behalf of
+// others then the returned error describes this.
 func (c *RevisionClient) SetReview(ctx context.Context, changeID, revisionID string, ri *ReviewInput) error {
 	var x interface{}
-	return c.Call(ctx, http.MethodPost, fmt.Sprintf("/changes/%v/revisions/%v/review", changeID, revisionID), ri, &x)
+	if err := c.Call(ctx, http.MethodPost, fmt.Sprintf("/changes/%v/revisions/%v/review", changeID, revisionID), ri, &x); err != nil {
+		var ce *CallError
+		if errors.As(err, &ce) && (ce.StatusCode() == http.StatusForbidden || ce.StatusCode() == http.StatusBadRequest) {
+			if m := labelNotPermittedRE.FindStringSubmatch(ce.Message()); m != nil {
+				return &LabelNotPermittedError{Label: m[1], Err: ce}
+			}
+		}
+		if ri != nil && ri.OnBehalfOf != "" {
+			return wrapStatus(err, http.StatusForbidden, fmt.Sprintf("not permitted to review on behalf of %q", ri.OnBehalfOf))
+		}
+		return err
+	}
+	return nil
+}
+
+// labelNotPermittedRE matches the error message Gerrit returns when applying a
+// label which the user is not permitted to set.
+var labelNotPermittedRE = regexp.MustCompile(`[Aa]pplying label "([^"]+)".*(?:restricted|not permitted)`)
+
+// LabelNotPermittedError is returned by SetReview when the review applies a label
+// (or label value) which the user is not permitted to set.
+type LabelNotPermittedError struct {
+	Label string // Name of the label.
+	Err   *CallError
+}
+
+func (e *LabelNotPermittedError) Error() string {
+	return fmt.Sprintf("applying label %q not permitted: %v", e.Label, e.Err.Message())
+}
+
+func (e *LabelNotPermittedError) Unwrap() error { return e.Err }
+
+// PreviewFix previews the effect of applying the fix suggestion with the given
+// ID, returning a mapping PATH -> DiffInfo for each file the fix modifies.
+// https://gerrit-review.googlesource.com/Documentation/rest-api-changes.html#preview-stored-fix
+func (c *RevisionClient) PreviewFix(ctx context.Context, changeID, revisionID, fixID string) (map[string]DiffInfo, error) {
+	var x map[string]DiffInfo
+	if err := c.Call(ctx, http.MethodGet, fmt.Sprintf("/changes/%v/revisions/%v/fixes/%v/preview", changeID, revisionID, fixID), nil, &x); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// GetCommit retrieves a parsed commit of a revision, including any web links.
+// https://gerrit-review.googlesource.com/Documentation/rest-api-changes.html#get-commit
+func (c *RevisionClient) GetCommit(ctx context.Context, changeID, revisionID string) (*CommitInfo, error) {
+	x := &CommitInfo{}
+	if err := c.Call(ctx, http.MethodGet, fmt.Sprintf("/changes/%v/revisions/%v/commit?links", changeID, revisionID), nil, x); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// ListFiles lists the files that were modified, added or deleted in a revision,
+// returning a mapping PATH -> FileInfo.
+// https://gerrit-review.googlesource.com/Documentation/rest-api-changes.html#list-files
+func (c *RevisionClient) ListFiles(ctx context.Context, changeID, revisionID string) (map[string]FileInfo, error) {
+	var x map[string]FileInfo
+	if err := c.Call(ctx, http.MethodGet, fmt.Sprintf("/changes/%v/revisions/%v/files", changeID, revisionID), nil, &x); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// ListReviewedFiles lists the paths of the files in a revision which the caller
+// has marked as reviewed.
+// https://gerrit-review.googlesource.com/Documentation/rest-api-changes.html#list-files
+func (c *RevisionClient) ListReviewedFiles(ctx context.Context, changeID, revisionID string) ([]string, error) {
+	var x []string
+	if err := c.Call(ctx, http.MethodGet, fmt.Sprintf("/changes/%v/revisions/%v/files?reviewed", changeID, revisionID), nil, &x); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// SetFileReviewed marks (or unmarks) a file in a revision as reviewed by the caller.
+// https://gerrit-review.googlesource.com/Documentation/rest-api-changes.html#set-reviewed
+// https://gerrit-review.googlesource.com/Documentation/rest-api-changes.html#delete-reviewed
+func (c *RevisionClient) SetFileReviewed(ctx context.Context, changeID, revisionID, filePath string, reviewed bool) error {
+	method := http.MethodPut
+	if !reviewed {
+		method = http.MethodDelete
+	}
+
+	// Gerrit responds without a body (201, 200 or 204).
+	response, err := c.Client.open(ctx, method, fmt.Sprintf("/changes/%v/revisions/%v/files/%v/reviewed", changeID, revisionID, url.PathEscape(filePath)), "", nil)
+	if err != nil {
+		return err
+	}
+	return response.Body.Close()
+}
+
+// FileInfo contains information about a file in a patch set.
+// https://gerrit-review.googlesource.com/Documentation/rest-api-changes.html#file-info
+type FileInfo struct {
+	Status        string `json:"status,omitempty"`         // The status of the file (A, D, R, C, W). Not set if the file was modified.
+	Binary        bool   `json:"binary,omitempty"`         // Whether the file is binary.
+	OldPath       string `json:"old_path,omitempty"`       // The old file path. Only set if the file was renamed or copied.
+	LinesInserted int    `json:"lines_inserted,omitempty"` // Number of inserted lines. Not set for binary files or if no lines were inserted.
+	LinesDeleted  int    `json:"lines_deleted,omitempty"`  // Number of deleted lines. Not set for binary files or if no lines were deleted.
+	SizeDelta     int64  `json:"size_delta"`               // Number of bytes by which the file size increased/decreased.
+	Size          int64  `json:"size"`                     // File size in bytes.
+}
+
+// GetRevisionActions retrieves the actions available on a revision, as a map of
+// action name to ActionInfo.
+// https://gerrit-review.googlesource.com/Documentation/rest-api-changes.html#get-revision-actions
+func (c *RevisionClient) GetRevisionActions(ctx context.Context, changeID, revisionID string) (map[string]ActionInfo, error) {
+	var x map[string]ActionInfo
+	if err := c.Call(ctx, http.MethodGet, fmt.Sprintf("/changes/%v/revisions/%v/actions", changeID, revisionID), nil, &x); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// ActionInfo describes a REST API call the client can make to manipulate a resource.
+// https://gerrit-review.googlesource.com/Documentation/rest-api-changes.html#action-info
+type ActionInfo struct {
+	Method  string `json:"method,omitempty"`  // HTTP method to use with the action. Most actions use POST, PUT or DELETE. Absent if GET.
+	Label   string `json:"label,omitempty"`   // Short title to display to a user describing the action.
+	Title   string `json:"title,omitempty"`   // Longer text to display describing the action.
+	Enabled bool   `json:"enabled,omitempty"` // If true the action is permitted at this time and the caller is likely allowed to execute it. Absent if false.
+}
+
+// CherryPick cherry picks a revision to a destination branch, returning the
+// newly created change.  If input.AllowConflicts is set then the change may be
+// created with conflicts, in which case ContainsGitConflicts is set on the
+// returned change.
+// https://gerrit-review.googlesource.com/Documentation/rest-api-changes.html#cherry-pick
+func (c *RevisionClient) CherryPick(ctx context.Context, changeID, revisionID string, input *CherryPickInput) (*ChangeInfo, error) {
+	x := &ChangeInfo{}
+	if err := c.Call(ctx, http.MethodPost, fmt.Sprintf("/changes/%v/revisions/%v/cherrypick", changeID, revisionID), input, x); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// CherryPickInput contains information for cherry-picking a change to a new branch.
+// https://gerrit-review.googlesource.com/Documentation/rest-api-changes.html#cherrypick-input
+type CherryPickInput struct {
+	Message        string `json:"message,omitempty"`         // Commit message for the cherry-pick change. If not set, the commit message of the cherry-picked commit is used.
+	Destination    string `json:"destination"`               // Destination branch.
+	Base           string `json:"base,omitempty"`            // 40-hex digit SHA-1 of the commit which will be the parent commit of the newly created change.
+	Notify         string `json:"notify,omitempty"`          // Notify handling that defines to whom email notifications should be sent after the cherry-pick. Allowed values are NONE, OWNER, OWNER_REVIEWERS and ALL. Defaults to ALL.
+	KeepReviewers  bool   `json:"keep_reviewers,omitempty"`  // If true, carries reviewers and ccs over from original change to newly created one.
+	AllowConflicts bool   `json:"allow_conflicts,omitempty"` // If true, the cherry-pick uses content merge and succeeds also if there are conflicts.
+}
+
+// Errors returned by Rebase when Gerrit refuses to rebase the change (both are
+// reported as 409 Conflict).
+var (
+	ErrAlreadyUpToDate = errors.New("change is already up to date")
+	ErrRebaseConflict  = errors.New("change could not be rebased due to a conflict")
+)
+
+// Rebase rebases a revision, returning the updated change.  If the change is
+// already up to date then the returned error wraps ErrAlreadyUpToDate, and if
+// the rebase would cause a merge conflict then it wraps ErrRebaseConflict.  A nil
+// input rebases onto the tip of the destination branch.
+// https://gerrit-review.googlesource.com/Documentation/rest-api-changes.html#rebase-revision
+func (c *RevisionClient) Rebase(ctx context.Context, changeID, revisionID string, input *RebaseInput) (*ChangeInfo, error) {
+	if input == nil {
+		input = &RebaseInput{}
+	}
+	x := &ChangeInfo{}
+	if err := c.Call(ctx, http.MethodPost, fmt.Sprintf("/changes/%v/revisions/%v/rebase", changeID, revisionID), input, x); err != nil {
+		var ce *CallError
+		if errors.As(err, &ce) && ce.StatusCode() == http.StatusConflict {
+			msg := strings.ToLower(ce.Message())
+			switch {
+			case strings.Contains(msg, "up to date"):
+				return nil, fmt.Errorf("%w: %v", ErrAlreadyUpToDate, ce.Message())
+			case strings.Contains(msg, "conflict"):
+				return nil, fmt.Errorf("%w: %v", ErrRebaseConflict, ce.Message())
+			}
+		}
+		return nil, err
+	}
+	return x, nil
+}
+
+// RebaseInput contains information for changing the parent when rebasing.
+// https://gerrit-review.googlesource.com/Documentation/rest-api-changes.html#rebase-input
+type RebaseInput struct {
+	Base string `json:"base,omitempty"` // The new parent revision. If not set, the change is rebased onto the tip of the destination branch.
 }
 
 // ReviewInput contains information for adding a review to a revision.
 // https://gerrit-review.googlesource.com/Documentation/rest-api-changes.html#review-input
 type ReviewInput struct {
-	Message string         `json:"message"`
-	Labels  map[string]int `json:"labels"`
+	Message       string                `json:"message"`
+	Labels        map[string]int        `json:"labels"`
+	Tag           string                `json:"tag,omitempty"`            // Tag applied to the review comments, messages and votes (e.g. "autogenerated:ci"). Tags prefixed with "autogenerated:" are collapsed in the web UI.
+	Reviewers     []ReviewerInput       `json:"reviewers,omitempty"`      // Reviewers to add (or remove, using State ReviewerStateRemoved) to the change.
+	OnBehalfOf    string                `json:"on_behalf_of,omitempty"`   // Account ID, name, email or username of the user on whose behalf the review should be posted. The caller must have the "Label - Edit on behalf of" permission.
+	Notify        string                `json:"notify,omitempty"`         // Notify handling that defines to whom email notifications should be sent after the review is stored (NotifyNone, NotifyOwner, NotifyOwnerReviewers, NotifyAll).
+	NotifyDetails map[string]NotifyInfo `json:"notify_details,omitempty"` // Additional information about whom to notify as a map of recipient type (RecipientTo, RecipientCC, RecipientBCC) to NotifyInfo.
 }
@@ -0,0 +1,166 @@
+package gerrit
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestPreviewFix(t *testing.T) {
+	c, done := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if want := "/a/changes/1/revisions/current/fixes/fix-1/preview"; r.URL.Path != want {
+			t.Errorf("path = %q, expected %q", r.URL.Path, want)
+		}
+		writeJSON(w, `{
+			"main.go": {
+				"meta_a": {"name": "main.go", "content_type": "text/x-go", "lines": 3},
+				"meta_b": {"name": "main.go", "content_type": "text/x-go", "lines": 3},
+				"change_type": "MODIFIED",
+				"diff_header": ["diff --git a/main.go b/main.go"],
+				"content": [
+					{"ab": ["package main", ""]},
+					{"a": ["func mian() {}"], "b": ["func main() {}"]}
+				]
+			}
+		}`)
+	})
+	defer done()
+
+	rc := &RevisionClient{Client: c}
+	got, err := rc.PreviewFix(context.Background(), "1", "current", "fix-1")
+	if err != nil {
+		t.Fatalf("PreviewFix() = %v", err)
+	}
+
+	meta := &DiffFileMetaInfo{Name: "main.go", ContentType: "text/x-go", Lines: 3}
+	want := map[string]DiffInfo{
+		"main.go": {
+			MetaA:      meta,
+			MetaB:      meta,
+			ChangeType: "MODIFIED",
+			DiffHeader: []string{"diff --git a/main.go b/main.go"},
+			Content: []DiffContent{
+				{AB: []string{"package main", ""}},
+				{A: []string{"func mian() {}"}, B: []string{"func main() {}"}},
+			},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("PreviewFix() = %+v, expected %+v", got, want)
+	}
+}
+
+func TestGetCommit(t *testing.T) {
+	c, done := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := r.URL.Query()["links"]; !ok {
+			t.Errorf("links not requested: %v", r.URL)
+		}
+		writeJSON(w, `{
+			"commit": "674ac754f91e64a0efb8087e59a176484bd534d1",
+			"parents": [{"commit": "1eee2c9d8f352483781e772f35dc586a69ff5646", "subject": "Parent"}],
+			"author": {"name": "Alice", "email": "alice@example.com", "date": "2020-01-02 03:04:05.000000000", "tz": 60},
+			"committer": {"name": "Alice", "email": "alice@example.com", "date": "2020-01-02 03:04:05.000000000", "tz": 60},
+			"subject": "Fix the thing",
+			"message": "Fix the thing\n\nChange-Id: I0123\n",
+			"web_links": [
+				{"name": "gitiles", "url": "https://gerrit.example.com/plugins/gitiles/p/+/674ac754f91e64a0efb8087e59a176484bd534d1", "image_url": "https://gerrit.example.com/gitiles.png"}
+			]
+		}`)
+	})
+	defer done()
+
+	rc := &RevisionClient{Client: c}
+	got, err := rc.GetCommit(context.Background(), "1", "current")
+	if err != nil {
+		t.Fatalf("GetCommit() = %v", err)
+	}
+
+	want := []WebLinkInfo{{
+		Name:     "gitiles",
+		URL:      "https://gerrit.example.com/plugins/gitiles/p/+/674ac754f91e64a0efb8087e59a176484bd534d1",
+		ImageURL: "https://gerrit.example.com/gitiles.png",
+	}}
+	if !reflect.DeepEqual(got.WebLinks, want) {
+		t.Errorf("WebLinks = %+v, expected %+v", got.WebLinks, want)
+	}
+	if got.Commit != "674ac754f91e64a0efb8087e59a176484bd534d1" || len(got.Parents) != 1 || got.Author.TZ != 60 {
+		t.Errorf("GetCommit() = %+v", got)
+	}
+}
+
+func TestSetReviewLabelNotPermitted(t *testing.T) {
+	c, done := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		fmt.Fprint(w, "Applying label \"Code-Review\": 2 is restricted\n")
+	})
+	defer done()
+
+	rc := &RevisionClient{Client: c}
+	err := rc.SetReview(context.Background(), "1", "current", &ReviewInput{Labels: map[string]int{"Code-Review": 2}})
+
+	var lerr *LabelNotPermittedError
+	if !errors.As(err, &lerr) {
+		t.Fatalf("SetReview() = %v, expected *LabelNotPermittedError", err)
+	}
+	if lerr.Label != "Code-Review" {
+		t.Errorf("Label = %q, expected Code-Review", lerr.Label)
+	}
+	if lerr.Err.StatusCode() != http.StatusForbidden {
+		t.Errorf("StatusCode() = %d, expected %d", lerr.Err.StatusCode(), http.StatusForbidden)
+	}
+}
+
+func TestReviewInputReviewers(t *testing.T) {
+	ri := &ReviewInput{
+		Message: "Reassigning",
+		Reviewers: []ReviewerInput{
+			{Reviewer: "alice@example.com", State: ReviewerStateRemoved},
+			{Reviewer: "bob@example.com"},
+		},
+	}
+	b, err := json.Marshal(ri)
+	if err != nil {
+		t.Fatalf("json.Marshal() = %v", err)
+	}
+
+	var got map[string]interface{}
+	if err := json.Unmarshal(b, &got); err != nil {
+		t.Fatalf("json.Unmarshal() = %v", err)
+	}
+	want := []interface{}{
+		map[string]interface{}{"reviewer": "alice@example.com", "state": "REMOVED"},
+		map[string]interface{}{"reviewer": "bob@example.com"},
+	}
+	if !reflect.DeepEqual(got["reviewers"], want) {
+		t.Errorf("reviewers = %v, expected %v", got["reviewers"], want)
+	}
+}
+
+func TestRebaseNilInput(t *testing.T) {
+	var body string
+	c, done := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/a/changes/1/revisions/current/rebase" {
+			t.Errorf("unexpected request: %v %v", r.Method, r.URL)
+		}
+		b, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			t.Errorf("could not read request body: %v", err)
+		}
+		body = string(b)
+		writeJSON(w, `{"_number": 1}`)
+	})
+	defer done()
+
+	rc := &RevisionClient{Client: c}
+	if _, err := rc.Rebase(context.Background(), "1", "current", nil); err != nil {
+		t.Fatalf("Rebase() = %v", err)
+	}
+	if body != "{}" {
+		t.Errorf("request body = %q, expected %q", body, "{}")
+	}
+}
@@ -0,0 +1,171 @@
+package gerrit
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// Reviewer states.
+const (
+	ReviewerStateReviewer = "REVIEWER"
+	ReviewerStateCC       = "CC"
+	ReviewerStateRemoved  = "REMOVED"
+)
+
+// ReviewerInput contains information for adding a reviewer to a change.
+// https://gerrit-review.googlesource.com/Documentation/rest-api-changes.html#reviewer-input
+type ReviewerInput struct {
+	Reviewer  string `json:"reviewer"`            // The ID of one account that should be added as reviewer or the ID of one group for which all members should be added as reviewers.
+	State     string `json:"state,omitempty"`     // Add reviewer in this state. Possible reviewer states are REVIEWER and CC (and REMOVED, when used in ReviewInput). If not given, defaults to REVIEWER.
+	Confirmed bool   `json:"confirmed,omitempty"` // Whether adding the reviewer is confirmed (required for large groups).
+	Notify    string `json:"notify,omitempty"`    // Notify handling that defines to whom email notifications should be sent after the reviewer is added.
+}
+
+// ReviewerInfo contains information about a reviewer and their votes on a change.
+// https://gerrit-review.googlesource.com/Documentation/rest-api-changes.html#reviewer-info
+type ReviewerInfo struct {
+	AccountInfo
+
+	Approvals map[string]string `json:"approvals"` // The approvals of the reviewer as a map that maps the label names to the approval values.
+}
+
+// AddReviewerResult describes the result of adding a reviewer to a change.
+// https://gerrit-review.googlesource.com/Documentation/rest-api-changes.html#add-reviewer-result
+type AddReviewerResult struct {
+	Input     string         `json:"input"`               // Value of the reviewer field from ReviewerInput set while adding the reviewer.
+	Reviewers []ReviewerInfo `json:"reviewers,omitempty"` // The newly added reviewers.
+	CCs       []ReviewerInfo `json:"ccs,omitempty"`       // The newly CCed accounts.
+	Error     string         `json:"error,omitempty"`     // Error message explaining why the reviewer could not be added.
+	Confirm   bool           `json:"confirm,omitempty"`   // Whether adding the reviewer requires confirmation.
+}
+
+// State returns the state the added accounts ended up in: ReviewerStateReviewer
+// if any were added as reviewers, ReviewerStateCC if they were only CCed, or
+// the empty string if nobody was added (see Error and Confirm).
+func (r *AddReviewerResult) State() string {
+	switch {
+	case len(r.Reviewers) > 0:
+		return ReviewerStateReviewer
+	case len(r.CCs) > 0:
+		return ReviewerStateCC
+	}
+	return ""
+}
+
+// AddReviewer adds a reviewer (or CC) to a change.  Gerrit may add the account
+// as a CC rather than a reviewer (see AddReviewerResult.State), and adding a
+// large group requires the addition to be confirmed (see ReviewerInput.Confirmed
+// and AddReviewerResult.Confirm).
+// https://gerrit-review.googlesource.com/Documentation/rest-api-changes.html#add-reviewer
+func (c *ChangesClient) AddReviewer(ctx context.Context, changeID string, input *ReviewerInput) (*AddReviewerResult, error) {
+	x := &AddReviewerResult{}
+	if err := c.Client.Call(ctx, http.MethodPost, "/changes/"+changeID+"/reviewers", input, x); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// AddCC adds an account (or group) to a change as a CC.
+func (c *ChangesClient) AddCC(ctx context.Context, changeID, account string) (*AddReviewerResult, error) {
+	return c.AddReviewer(ctx, changeID, &ReviewerInput{
+		Reviewer: account,
+		State:    ReviewerStateCC,
+	})
+}
+
+// ListReviewers lists the reviewers of a change as a map of reviewer state
+// (ReviewerStateReviewer, ReviewerStateCC, ReviewerStateRemoved) to accounts.
+func (c *ChangesClient) ListReviewers(ctx context.Context, changeID string) (map[string][]AccountInfo, error) {
+	ch, err := c.GetChange(ctx, changeID, OptionDetailedLabels, OptionDetailedAccounts)
+	if err != nil {
+		return nil, err
+	}
+	return ch.Reviewers, nil
+}
+
+// DeleteReviewerInput contains options for the deletion of a reviewer.
+// https://gerrit-review.googlesource.com/Documentation/rest-api-changes.html#delete-reviewer-input
+type DeleteReviewerInput struct {
+	Notify string `json:"notify,omitempty"` // Notify handling that defines to whom email notifications should be sent after the reviewer is deleted.
+}
+
+// DeleteReviewer deletes a reviewer from a change.
+// https://gerrit-review.googlesource.com/Documentation/rest-api-changes.html#delete-reviewer
+func (c *ChangesClient) DeleteReviewer(ctx context.Context, changeID, accountID string, input *DeleteReviewerInput) error {
+	if input == nil {
+		input = &DeleteReviewerInput{}
+	}
+	var x interface{}
+	return c.Client.Call(ctx, http.MethodPost, "/changes/"+changeID+"/reviewers/"+url.PathEscape(accountID)+"/delete", input, &x)
+}
+
+// SyncReviewers updates the reviewers of a change to match desired (a list of
+// usernames or emails), adding missing reviewers and removing any which are not
+// desired.  The change owner is never added or removed.  Returns the reviewers
+// which were actually added (i.e. not added as a CC by Gerrit) and removed.
+// Adding a reviewer which requires confirmation (e.g. a large group) is an error.
+func (c *ChangesClient) SyncReviewers(ctx context.Context, changeID string, desired []string, notify string) (added, removed []string, err error) {
+	ch, err := c.GetChange(ctx, changeID, OptionDetailedLabels, OptionDetailedAccounts)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	current := ch.Reviewers[ReviewerStateReviewer]
+	for _, d := range desired {
+		if accountMatches(ch.Owner, d) || containsAccount(current, d) {
+			continue
+		}
+		res, err := c.AddReviewer(ctx, changeID, &ReviewerInput{Reviewer: d, Notify: notify})
+		if err != nil {
+			return added, removed, fmt.Errorf("could not add reviewer %q: %w", d, err)
+		}
+		if res.Error != "" {
+			return added, removed, fmt.Errorf("could not add reviewer %q: %v", d, res.Error)
+		}
+		if res.Confirm {
+			return added, removed, fmt.Errorf("could not add reviewer %q: confirmation required", d)
+		}
+		if res.State() != ReviewerStateReviewer {
+			continue
+		}
+		added = append(added, d)
+	}
+
+	for _, a := range current {
+		if a.AccountID == ch.Owner.AccountID || containsID(desired, a) {
+			continue
+		}
+		key := accountKey(a)
+		if err := c.DeleteReviewer(ctx, changeID, strconv.Itoa(a.AccountID), &DeleteReviewerInput{Notify: notify}); err != nil {
+			return added, removed, fmt.Errorf("could not remove reviewer %q: %w", key, err)
+		}
+		removed = append(removed, key)
+	}
+	return added, removed, nil
+}
+
+// accountMatches returns true if id identifies the account (by username or email).
+func accountMatches(a AccountInfo, id string) bool {
+	return id != "" && (a.Username == id || a.Email == id)
+}
+
+func containsAccount(as []AccountInfo, id string) bool {
+	for _, a := range as {
+		if accountMatches(a, id) {
+			return true
+		}
+	}
+	return false
+}
+
+func containsID(ids []string, a AccountInfo) bool {
+	for _, id := range ids {
+		if accountMatches(a, id) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,161 @@
+package gerrit
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestSyncReviewers(t *testing.T) {
+	var deleted []string
+	c, done := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method + " " + r.URL.Path {
+		case "GET /a/changes/1":
+			writeJSON(w, `{
+				"_number": 1,
+				"owner": {"_account_id": 1, "username": "owner"},
+				"reviewers": {
+					"REVIEWER": [
+						{"_account_id": 1, "username": "owner"},
+						{"_account_id": 2, "username": "alice"},
+						{"_account_id": 3, "name": "Bob"}
+					]
+				}
+			}`)
+
+		case "POST /a/changes/1/reviewers":
+			var in ReviewerInput
+			if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+				t.Errorf("could not decode reviewer input: %v", err)
+			}
+			switch in.Reviewer {
+			case "carol":
+				writeJSON(w, `{"input": "carol", "reviewers": [{"_account_id": 4, "username": "carol"}]}`)
+			case "dave":
+				writeJSON(w, `{"input": "dave", "ccs": [{"_account_id": 5, "username": "dave"}]}`)
+			default:
+				t.Errorf("unexpected reviewer: %q", in.Reviewer)
+			}
+
+		case "POST /a/changes/1/reviewers/3/delete":
+			deleted = append(deleted, "3")
+			w.WriteHeader(http.StatusNoContent)
+
+		default:
+			t.Errorf("unexpected request: %v %v", r.Method, r.URL)
+			http.NotFound(w, r)
+		}
+	})
+	defer done()
+
+	gcc := &ChangesClient{Client: c}
+	added, removed, err := gcc.SyncReviewers(context.Background(), "1", []string{"alice", "carol", "dave"}, "")
+	if err != nil {
+		t.Fatalf("SyncReviewers() = %v", err)
+	}
+	if want := []string{"carol"}; !reflect.DeepEqual(added, want) {
+		t.Errorf("added = %v, expected %v", added, want)
+	}
+	if want := []string{"Bob"}; !reflect.DeepEqual(removed, want) {
+		t.Errorf("removed = %v, expected %v", removed, want)
+	}
+	if want := []string{"3"}; !reflect.DeepEqual(deleted, want) {
+		t.Errorf("deleted account IDs = %v, expected %v", deleted, want)
+	}
+}
+
+func TestSyncReviewersConfirm(t *testing.T) {
+	c, done := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method + " " + r.URL.Path {
+		case "GET /a/changes/1":
+			writeJSON(w, `{"_number": 1, "owner": {"_account_id": 1, "username": "owner"}}`)
+		case "POST /a/changes/1/reviewers":
+			writeJSON(w, `{"input": "big-group", "confirm": true, "error": "The group big-group has 100 members. Do you want to add them all as reviewers?"}`)
+		default:
+			t.Errorf("unexpected request: %v %v", r.Method, r.URL)
+		}
+	})
+	defer done()
+
+	gcc := &ChangesClient{Client: c}
+	added, _, err := gcc.SyncReviewers(context.Background(), "1", []string{"big-group"}, "")
+	if err == nil {
+		t.Errorf("SyncReviewers() = nil, expected error")
+	}
+	if len(added) != 0 {
+		t.Errorf("added = %v, expected none", added)
+	}
+}
+
+func TestAddCC(t *testing.T) {
+	c, done := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		var in ReviewerInput
+		if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+			t.Errorf("could not decode reviewer input: %v", err)
+		}
+		if in.Reviewer != "carol" || in.State != ReviewerStateCC {
+			t.Errorf("reviewer input = %+v, expected carol as CC", in)
+		}
+		writeJSON(w, `{"input": "carol", "ccs": [{"_account_id": 4, "username": "carol", "approvals": {}}]}`)
+	})
+	defer done()
+
+	gcc := &ChangesClient{Client: c}
+	res, err := gcc.AddCC(context.Background(), "1", "carol")
+	if err != nil {
+		t.Fatalf("AddCC() = %v", err)
+	}
+	if got := res.State(); got != ReviewerStateCC {
+		t.Errorf("State() = %q, expected %q", got, ReviewerStateCC)
+	}
+	if len(res.CCs) != 1 || res.CCs[0].Username != "carol" || len(res.Reviewers) != 0 {
+		t.Errorf("AddCC() = %+v, expected carol as the only CC", res)
+	}
+}
+
+func TestAddReviewerGroupConfirm(t *testing.T) {
+	c, done := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		var in ReviewerInput
+		if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+			t.Errorf("could not decode reviewer input: %v", err)
+		}
+		if !in.Confirmed {
+			writeJSON(w, `{
+				"input": "big-group",
+				"confirm": true,
+				"error": "The group big-group has 11 members. Do you want to add them all as reviewers?"
+			}`)
+			return
+		}
+		writeJSON(w, `{
+			"input": "big-group",
+			"reviewers": [
+				{"_account_id": 10, "username": "alice", "approvals": {"Code-Review": " 0"}},
+				{"_account_id": 11, "username": "bob", "approvals": {"Code-Review": " 0"}}
+			]
+		}`)
+	})
+	defer done()
+
+	gcc := &ChangesClient{Client: c}
+	res, err := gcc.AddReviewer(context.Background(), "1", &ReviewerInput{Reviewer: "big-group"})
+	if err != nil {
+		t.Fatalf("AddReviewer() = %v", err)
+	}
+	if !res.Confirm || res.Error == "" || res.State() != "" {
+		t.Errorf("AddReviewer() = %+v, expected confirmation to be required", res)
+	}
+
+	res, err = gcc.AddReviewer(context.Background(), "1", &ReviewerInput{Reviewer: "big-group", Confirmed: true})
+	if err != nil {
+		t.Fatalf("AddReviewer() with confirmation = %v", err)
+	}
+	if res.Confirm || res.Error != "" || res.State() != ReviewerStateReviewer || len(res.Reviewers) != 2 {
+		t.Errorf("AddReviewer() with confirmation = %+v, expected two reviewers", res)
+	}
+	if got := res.Reviewers[0].Approvals["Code-Review"]; got != " 0" {
+		t.Errorf("Approvals[Code-Review] = %q, expected \" 0\"", got)
+	}
+}
@@ -0,0 +1,70 @@
+package stream
+
+import (
+	"context"
+	"time"
+)
+
+// Batcher collects events over a time window and emits them grouped by change.
+type Batcher struct {
+	// Window is the duration over which events are collected, starting
+	// from the first event received after the previous flush.
+	Window time.Duration
+}
+
+// Run reads events from in and, at the end of each window, sends the events for
+// each change as a single batch on out (in the order each change was first seen
+// within the window).
+// Events not associated with a change are batched together.
+//
+// Run returns when in is closed or ctx is done, flushing any pending events
+// before closing out.  Callers must continue to receive from out until it is
+// closed.
+func (b *Batcher) Run(ctx context.Context, in <-chan *Event, out chan<- []*Event) error {
+	defer close(out)
+
+	var (
+		order  []int
+		groups map[int][]*Event
+		timer  *time.Timer
+		tick   <-chan time.Time
+	)
+
+	flush := func() {
+		for _, n := range order {
+			out <- groups[n]
+		}
+		order, groups = nil, nil
+		if timer != nil {
+			timer.Stop()
+		}
+		timer, tick = nil, nil
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			flush()
+			return ctx.Err()
+
+		case <-tick:
+			flush()
+
+		case e, ok := <-in:
+			if !ok {
+				flush()
+				return nil
+			}
+			if groups == nil {
+				groups = make(map[int][]*Event)
+				timer = time.NewTimer(b.Window)
+				tick = timer.C
+			}
+			n, _ := ChangeNumber(e)
+			if _, ok := groups[n]; !ok {
+				order = append(order, n)
+			}
+			groups[n] = append(groups[n], e)
+		}
+	}
+}
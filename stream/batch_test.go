@@ -0,0 +1,54 @@
+package stream
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func changeEvent(n int) *Event {
+	return &Event{EventType: &CommentAdded{Change: Change{Number: n}}}
+}
+
+func TestBatcher(t *testing.T) {
+	in := make(chan *Event)
+	out := make(chan []*Event)
+	b := &Batcher{Window: time.Hour}
+
+	errc := make(chan error, 1)
+	go func() {
+		errc <- b.Run(context.Background(), in, out)
+	}()
+
+	e1, e2, e3 := changeEvent(1), changeEvent(2), changeEvent(1)
+	go func() {
+		in <- e1
+		in <- e2
+		in <- e3
+		close(in)
+	}()
+
+	var got [][]*Event
+	for batch := range out {
+		got = append(got, batch)
+	}
+	if err := <-errc; err != nil {
+		t.Fatalf("Run() = %v", err)
+	}
+
+	want := [][]*Event{{e1, e3}, {e2}}
+	if len(got) != len(want) {
+		t.Fatalf("got %d batches, expected %d", len(got), len(want))
+	}
+	for i := range want {
+		if len(got[i]) != len(want[i]) {
+			t.Errorf("batch %d has %d events, expected %d", i, len(got[i]), len(want[i]))
+			continue
+		}
+		for j := range want[i] {
+			if got[i][j] != want[i][j] {
+				t.Errorf("batch %d event %d = %v, expected %v", i, j, got[i][j], want[i][j])
+			}
+		}
+	}
+}
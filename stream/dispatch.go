@@ -0,0 +1,124 @@
+package stream
+
+// Handler handles events passed to Dispatch.  Embed NopHandler to only
+// implement the methods for the events of interest.
+type Handler interface {
+	HandleAssigneeChanged(e *Event, x *AssigneeChanged) error
+	HandleChangeAbandoned(e *Event, x *ChangeAbandoned) error
+	HandleChangeDeleted(e *Event, x *ChangeDeleted) error
+	HandleChangeMerged(e *Event, x *ChangeMerged) error
+	HandleChangeRestored(e *Event, x *ChangeRestored) error
+	HandleCommentAdded(e *Event, x *CommentAdded) error
+	HandleDroppedOutput(e *Event, x *DroppedOutput) error
+	HandleHashtagsChanged(e *Event, x *HashtagsChanged) error
+	HandleProjectCreated(e *Event, x *ProjectCreated) error
+	HandlePatchsetCreated(e *Event, x *PatchsetCreated) error
+	HandleRefUpdated(e *Event, x *RefUpdated) error
+	HandleReviewerAdded(e *Event, x *ReviewerAdded) error
+	HandleReviewerDeleted(e *Event, x *ReviewerDeleted) error
+	HandleTopicChanged(e *Event, x *TopicChanged) error
+	HandleWIPStateChanged(e *Event, x *WIPStateChanged) error
+	HandlePrivateStateChanged(e *Event, x *PrivateStateChanged) error
+	HandleVoteDeleted(e *Event, x *VoteDeleted) error
+	HandleUnknownEventType(e *Event, x *UnknownEventType) error
+}
+
+// NopHandler is a Handler which ignores all events.
+type NopHandler struct{}
+
+// HandleAssigneeChanged implements Handler.
+func (NopHandler) HandleAssigneeChanged(*Event, *AssigneeChanged) error { return nil }
+
+// HandleChangeAbandoned implements Handler.
+func (NopHandler) HandleChangeAbandoned(*Event, *ChangeAbandoned) error { return nil }
+
+// HandleChangeDeleted implements Handler.
+func (NopHandler) HandleChangeDeleted(*Event, *ChangeDeleted) error { return nil }
+
+// HandleChangeMerged implements Handler.
+func (NopHandler) HandleChangeMerged(*Event, *ChangeMerged) error { return nil }
+
+// HandleChangeRestored implements Handler.
+func (NopHandler) HandleChangeRestored(*Event, *ChangeRestored) error { return nil }
+
+// HandleCommentAdded implements Handler.
+func (NopHandler) HandleCommentAdded(*Event, *CommentAdded) error { return nil }
+
+// HandleDroppedOutput implements Handler.
+func (NopHandler) HandleDroppedOutput(*Event, *DroppedOutput) error { return nil }
+
+// HandleHashtagsChanged implements Handler.
+func (NopHandler) HandleHashtagsChanged(*Event, *HashtagsChanged) error { return nil }
+
+// HandleProjectCreated implements Handler.
+func (NopHandler) HandleProjectCreated(*Event, *ProjectCreated) error { return nil }
+
+// HandlePatchsetCreated implements Handler.
+func (NopHandler) HandlePatchsetCreated(*Event, *PatchsetCreated) error { return nil }
+
+// HandleRefUpdated implements Handler.
+func (NopHandler) HandleRefUpdated(*Event, *RefUpdated) error { return nil }
+
+// HandleReviewerAdded implements Handler.
+func (NopHandler) HandleReviewerAdded(*Event, *ReviewerAdded) error { return nil }
+
+// HandleReviewerDeleted implements Handler.
+func (NopHandler) HandleReviewerDeleted(*Event, *ReviewerDeleted) error { return nil }
+
+// HandleTopicChanged implements Handler.
+func (NopHandler) HandleTopicChanged(*Event, *TopicChanged) error { return nil }
+
+// HandleWIPStateChanged implements Handler.
+func (NopHandler) HandleWIPStateChanged(*Event, *WIPStateChanged) error { return nil }
+
+// HandlePrivateStateChanged implements Handler.
+func (NopHandler) HandlePrivateStateChanged(*Event, *PrivateStateChanged) error { return nil }
+
+// HandleVoteDeleted implements Handler.
+func (NopHandler) HandleVoteDeleted(*Event, *VoteDeleted) error { return nil }
+
+// HandleUnknownEventType implements Handler.
+func (NopHandler) HandleUnknownEventType(*Event, *UnknownEventType) error { return nil }
+
+// Dispatch calls the method of h which handles the type of e.
+func Dispatch(e *Event, h Handler) error {
+	switch x := e.EventType.(type) {
+	case *AssigneeChanged:
+		return h.HandleAssigneeChanged(e, x)
+	case *ChangeAbandoned:
+		return h.HandleChangeAbandoned(e, x)
+	case *ChangeDeleted:
+		return h.HandleChangeDeleted(e, x)
+	case *ChangeMerged:
+		return h.HandleChangeMerged(e, x)
+	case *ChangeRestored:
+		return h.HandleChangeRestored(e, x)
+	case *CommentAdded:
+		return h.HandleCommentAdded(e, x)
+	case *DroppedOutput:
+		return h.HandleDroppedOutput(e, x)
+	case *HashtagsChanged:
+		return h.HandleHashtagsChanged(e, x)
+	case *ProjectCreated:
+		return h.HandleProjectCreated(e, x)
+	case *PatchsetCreated:
+		return h.HandlePatchsetCreated(e, x)
+	case *RefUpdated:
+		return h.HandleRefUpdated(e, x)
+	case *ReviewerAdded:
+		return h.HandleReviewerAdded(e, x)
+	case *ReviewerDeleted:
+		return h.HandleReviewerDeleted(e, x)
+	case *TopicChanged:
+		return h.HandleTopicChanged(e, x)
+	case *WIPStateChanged:
+		return h.HandleWIPStateChanged(e, x)
+	case *PrivateStateChanged:
+		return h.HandlePrivateStateChanged(e, x)
+	case *VoteDeleted:
+		return h.HandleVoteDeleted(e, x)
+	case *UnknownEventType:
+		return h.HandleUnknownEventType(e, x)
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+package stream
+
+import (
+	"errors"
+	"testing"
+)
+
+// commentHandler records the comment-added and unknown events passed to it,
+// ignoring all others.
+type commentHandler struct {
+	NopHandler
+
+	err     error
+	called  []string
+	comment *CommentAdded
+	unknown *UnknownEventType
+}
+
+func (h *commentHandler) HandleCommentAdded(e *Event, x *CommentAdded) error {
+	h.called = append(h.called, x.Type())
+	h.comment = x
+	return h.err
+}
+
+func (h *commentHandler) HandleUnknownEventType(e *Event, x *UnknownEventType) error {
+	h.called = append(h.called, x.Type())
+	h.unknown = x
+	return h.err
+}
+
+func TestDispatch(t *testing.T) {
+	comment := &CommentAdded{Change: Change{Number: 1}}
+	unknown := &UnknownEventType{UnknownType: "custom-event"}
+
+	events := []EventType{
+		&AssigneeChanged{},
+		&ChangeAbandoned{},
+		&ChangeDeleted{},
+		&ChangeMerged{},
+		&ChangeRestored{},
+		comment,
+		&DroppedOutput{},
+		&HashtagsChanged{},
+		&ProjectCreated{},
+		&PatchsetCreated{},
+		&RefUpdated{},
+		&ReviewerAdded{},
+		&ReviewerDeleted{},
+		&TopicChanged{},
+		&WIPStateChanged{},
+		&PrivateStateChanged{},
+		&VoteDeleted{},
+		unknown,
+	}
+
+	h := &commentHandler{}
+	for _, x := range events {
+		if err := Dispatch(&Event{EventType: x}, h); err != nil {
+			t.Errorf("Dispatch(%v) = %v", x.Type(), err)
+		}
+	}
+
+	want := []string{EventTypeCommentAdded, "custom-event"}
+	if len(h.called) != len(want) || h.called[0] != want[0] || h.called[1] != want[1] {
+		t.Errorf("handled %v, expected %v", h.called, want)
+	}
+	if h.comment != comment {
+		t.Errorf("HandleCommentAdded() got %p, expected %p", h.comment, comment)
+	}
+	if h.unknown != unknown {
+		t.Errorf("HandleUnknownEventType() got %p, expected %p", h.unknown, unknown)
+	}
+}
+
+func TestDispatchError(t *testing.T) {
+	errHandle := errors.New("handle failed")
+	h := &commentHandler{err: errHandle}
+
+	if err := Dispatch(&Event{EventType: &CommentAdded{}}, h); err != errHandle {
+		t.Errorf("Dispatch() = %v, expected %v", err, errHandle)
+	}
+	if err := Dispatch(&Event{EventType: &ChangeMerged{}}, h); err != nil {
+		t.Errorf("Dispatch() of ignored event = %v, expected nil", err)
+	}
+}
@@ -0,0 +1,63 @@
+package stream
+
+import (
+	"context"
+	"path"
+)
+
+// RefFilter matches events against ref glob patterns (see path.Match), for
+// example "refs/heads/release-*".
+type RefFilter struct {
+	// Patterns to match against.  RefUpdated events are matched using the
+	// updated ref name, events associated with a change are matched using
+	// "refs/heads/" + the branch of the change.
+	Patterns []string
+
+	// PassNonRef determines whether events which are not associated with a
+	// ref (e.g. ProjectCreated or DroppedOutput) are passed.
+	PassNonRef bool
+}
+
+// Match returns true if the event passes the filter.
+func (f *RefFilter) Match(e *Event) bool {
+	var ref string
+	if ru, ok := e.EventType.(*RefUpdated); ok {
+		ref = ru.RefUpdate.RefName
+	} else if c, ok := eventChange(e); ok {
+		ref = "refs/heads/" + c.Branch
+	} else {
+		return f.PassNonRef
+	}
+
+	for _, p := range f.Patterns {
+		if ok, _ := path.Match(p, ref); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// Filter reads events from in and sends those which match the filter on out.
+// Filter returns when in is closed or ctx is done, closing out.
+func (f *RefFilter) Filter(ctx context.Context, in <-chan *Event, out chan<- *Event) error {
+	defer close(out)
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+
+		case e, ok := <-in:
+			if !ok {
+				return nil
+			}
+			if !f.Match(e) {
+				continue
+			}
+			select {
+			case out <- e:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+	}
+}
@@ -0,0 +1,35 @@
+package stream
+
+import "testing"
+
+func refUpdated(ref string) *Event {
+	return &Event{EventType: &RefUpdated{RefUpdate: RefUpdate{RefName: ref}}}
+}
+
+func TestRefFilterMatch(t *testing.T) {
+	f := &RefFilter{Patterns: []string{"refs/heads/release-*", "refs/heads/master"}}
+
+	tests := []struct {
+		e    *Event
+		want bool
+	}{
+		{refUpdated("refs/heads/release-1.0"), true},
+		{refUpdated("refs/heads/master"), true},
+		{refUpdated("refs/heads/feature"), false},
+		{refUpdated("refs/tags/release-1.0"), false},
+		{&Event{EventType: &CommentAdded{Change: Change{Branch: "release-2.0"}}}, true},
+		{&Event{EventType: &CommentAdded{Change: Change{Branch: "dev"}}}, false},
+		{&Event{EventType: &ProjectCreated{}}, false},
+	}
+
+	for _, tt := range tests {
+		if got := f.Match(tt.e); got != tt.want {
+			t.Errorf("Match(%+v) = %v, expected %v", tt.e.EventType, got, tt.want)
+		}
+	}
+
+	f.PassNonRef = true
+	if !f.Match(&Event{EventType: &ProjectCreated{}}) {
+		t.Errorf("Match(ProjectCreated) = false with PassNonRef, expected true")
+	}
+}
@@ -0,0 +1,82 @@
+package stream
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+)
+
+// maxLineLength is the maximum length of a line read by a Scanner.
+const maxLineLength = 16 << 20
+
+// LineError is returned when a line could not be decoded as an event.
+type LineError struct {
+	Line int // Line number (1-based).
+	Err  error
+}
+
+func (e *LineError) Error() string { return fmt.Sprintf("line %d: %v", e.Line, e.Err) }
+
+func (e *LineError) Unwrap() error { return e.Err }
+
+// Scanner reads newline-delimited JSON events (as output by
+// "gerrit stream-events"), skipping blank lines.
+type Scanner struct {
+	s     *bufio.Scanner
+	line  int
+	event *Event
+	err   error
+}
+
+// NewScanner returns a new Scanner which reads from r.
+func NewScanner(r io.Reader) *Scanner {
+	s := bufio.NewScanner(r)
+	s.Buffer(nil, maxLineLength)
+	return &Scanner{s: s}
+}
+
+// Scan advances the Scanner to the next event, which is then available from
+// Event.  Returns false when there are no more events or an error occurred (see
+// Err).
+func (s *Scanner) Scan() bool {
+	if s.err != nil {
+		return false
+	}
+	for s.s.Scan() {
+		s.line++
+		b := bytes.TrimSpace(s.s.Bytes())
+		if len(b) == 0 {
+			continue
+		}
+		e, err := UnmarshalEvent(b)
+		if err != nil {
+			s.err = &LineError{Line: s.line, Err: err}
+			return false
+		}
+		s.event = e
+		return true
+	}
+	s.err = s.s.Err()
+	return false
+}
+
+// Event returns the event read by the most recent call to Scan.
+func (s *Scanner) Event() *Event { return s.event }
+
+// Err returns the first error encountered by the Scanner.  Errors decoding
+// events are of type *LineError.
+func (s *Scanner) Err() error { return s.err }
+
+// ReadAll reads all events from r (see Scanner).
+func ReadAll(r io.Reader) ([]*Event, error) {
+	var out []*Event
+	s := NewScanner(r)
+	for s.Scan() {
+		out = append(out, s.Event())
+	}
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
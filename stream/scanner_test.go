@@ -0,0 +1,62 @@
+package stream
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestScanner(t *testing.T) {
+	in := "\n" +
+		`{"type": "change-merged", "eventCreatedOn": 1600000000, "change": {"number": 1}}` + "\n" +
+		"  \n" +
+		"\n" +
+		`{"type": "change-abandoned", "eventCreatedOn": 1600000100, "change": {"number": 2}}` + "\n"
+
+	es, err := ReadAll(strings.NewReader(in))
+	if err != nil {
+		t.Fatalf("ReadAll() = %v", err)
+	}
+	if len(es) != 2 {
+		t.Fatalf("got %d events, expected 2", len(es))
+	}
+	for i, want := range []int{1, 2} {
+		if n, _ := ChangeNumber(es[i]); n != want {
+			t.Errorf("event %d change number = %d, expected %d", i, n, want)
+		}
+	}
+}
+
+func TestScannerLineError(t *testing.T) {
+	in := "\n" +
+		"\n" +
+		`{"type": "change-merged", "eventCreatedOn": 1600000000, "change": {"number": 1}}` + "\n" +
+		"\t\n" +
+		`{"type": "change-merged", "change": ` + "\n" +
+		`{"type": "change-merged", "eventCreatedOn": 1600000100, "change": {"number": 2}}` + "\n"
+
+	s := NewScanner(strings.NewReader(in))
+	var n int
+	for s.Scan() {
+		n++
+	}
+	if n != 1 {
+		t.Errorf("scanned %d events before the error, expected 1", n)
+	}
+
+	for _, err := range []error{s.Err(), readAllErr(in)} {
+		var le *LineError
+		if !errors.As(err, &le) {
+			t.Errorf("error = %v, expected *LineError", err)
+			continue
+		}
+		if le.Line != 5 {
+			t.Errorf("LineError.Line = %d, expected 5", le.Line)
+		}
+	}
+}
+
+func readAllErr(in string) error {
+	_, err := ReadAll(strings.NewReader(in))
+	return err
+}
@@ -6,6 +6,7 @@ package stream
 import (
 	"encoding/json"
 	"strconv"
+	"strings"
 	"time"
 )
 
@@ -14,14 +15,33 @@ import (
 // Gerrit events API.
 type UnixTime time.Time
 
+// MarshalJSON implements json.Marshaler.  The zero UnixTime is encoded as 0.
 func (ut UnixTime) MarshalJSON() ([]byte, error) {
+	if time.Time(ut).IsZero() {
+		return []byte("0"), nil
+	}
 	return strconv.AppendInt(nil, time.Time(ut).Unix(), 10), nil
 }
 
+// UnmarshalJSON implements json.Unmarshaler.  Values can be integers or floats
+// (fractional seconds are truncated), and may be quoted.
 func (ut *UnixTime) UnmarshalJSON(b []byte) error {
-	n, err := strconv.ParseInt(string(b), 10, 32)
+	s := string(b)
+	if uq, err := strconv.Unquote(s); err == nil {
+		s = uq
+	}
+
+	n, err := strconv.ParseInt(s, 10, 64)
 	if err != nil {
-		return err
+		f, ferr := strconv.ParseFloat(s, 64)
+		if ferr != nil {
+			return err
+		}
+		n = int64(f)
+	}
+	if n == 0 {
+		*ut = UnixTime{}
+		return nil
 	}
 	*ut = UnixTime(time.Unix(n, 0))
 	return nil
@@ -41,19 +61,19 @@ type Account struct {
 // PatchSet refers to a specific patchset within a Change.
 // https://gerrit-review.googlesource.com/Documentation/json.html#patchSet
 type PatchSet struct {
-	Number         int      `json:"number"`
-	Revision       string   `json:"revision"`
-	Parents        []string `json:"parents"`
-	Ref            string   `json:"ref"`
-	Uploader       Account  `json:"uploader"`
-	Author         Account  `json:"author"`
-	CreatedOn      UnixTime `json:"createdOn"`
-	Kind           string   `json:"kind"`
-	Approvals      []Approval
-	Comments       []PatchsetComment
-	Files          []File
-	SizeInsertions int `json:"sizeInsertions"`
-	SizeDeletions  int `json:"sizeDeletions"`
+	Number         int               `json:"number"`
+	Revision       string            `json:"revision"`
+	Parents        []string          `json:"parents"`
+	Ref            string            `json:"ref"`
+	Uploader       Account           `json:"uploader"`
+	Author         Account           `json:"author"`
+	CreatedOn      UnixTime          `json:"createdOn"`
+	Kind           string            `json:"kind"`
+	Approvals      []Approval        `json:"approvals,omitempty"`
+	Comments       []PatchsetComment `json:"comments,omitempty"`
+	Files          []File            `json:"files,omitempty"`
+	SizeInsertions int               `json:"sizeInsertions"`
+	SizeDeletions  int               `json:"sizeDeletions"`
 }
 
 func (p *PatchSet) Accounts() []Account {
@@ -66,63 +86,89 @@ func (p *PatchSet) Accounts() []Account {
 // PatchsetComment is a comment added on a patchset by a reviewer.
 // https://gerrit-review.googlesource.com/Documentation/json.html#patchsetcomment
 type PatchsetComment struct {
-	File     string
-	Line     int
-	Reviewer Account
-	Message  string
+	File     string  `json:"file"`
+	Line     int     `json:"line"`
+	Reviewer Account `json:"reviewer"`
+	Message  string  `json:"message"`
 }
 
-// File contains information about a patch on a file.
+// File contains information about a patch on a file.  Unlike the REST API
+// FileInfo, the stream payload does not report whether a file is binary.
 // https://gerrit-review.googlesource.com/Documentation/json.html#file
 type File struct {
-	File       string
-	FileOld    string
-	Type       string
-	Insertions int
-	Deletions  int
+	File       string `json:"file"`
+	FileOld    string `json:"fileOld"`
+	Type       string `json:"type"`
+	Insertions int    `json:"insertions"`
+	Deletions  int    `json:"deletions"`
+}
+
+// File.Type values.
+const (
+	FileTypeAdded    = "ADDED"    // The file is being created/introduced by this patch.
+	FileTypeModified = "MODIFIED" // The file already exists, and has updated content.
+	FileTypeDeleted  = "DELETED"  // The file existed, but is being removed by this patch.
+	FileTypeRenamed  = "RENAMED"  // The file is renamed.
+	FileTypeCopied   = "COPIED"   // The file is copied from another file.
+	FileTypeRewrite  = "REWRITE"  // Sufficient amount of content changed to claim the file was rewritten.
+)
+
+// IsRename returns true if the file was renamed (see OldPath).
+func (f File) IsRename() bool { return f.Type == FileTypeRenamed }
+
+// IsDelete returns true if the file was deleted.
+func (f File) IsDelete() bool { return f.Type == FileTypeDeleted }
+
+// OldPath returns the path of the file before the patch: the original path of
+// renamed or copied files, and the path of the file otherwise.
+func (f File) OldPath() string {
+	if f.FileOld != "" {
+		return f.FileOld
+	}
+	return f.File
 }
 
 // Change represents the Gerrit change being reviewed, or that was already reviewed.
 // https://gerrit-review.googlesource.com/Documentation/json.html#change
 type Change struct {
-	Project         string       `json:"project"`
-	Branch          string       `json:"branch"`
-	ID              string       `json:"id"`
-	Number          int          `json:"number"`
-	Subject         string       `json:"subject"`
-	Owner           Account      `json:"owner"`
-	URL             string       `json:"url"`
-	CommitMessage   string       `json:"commitMessage"`
-	HashTags        []string     `json:"hashTags"`
-	CreatedOn       UnixTime     `json:"createdOn"`
-	LastUpdated     UnixTime     `json:"lastUpdated"`
-	Status          string       `json:"status"`
-	Open            bool         `json:"open"`
-	Private         bool         `json:"private"`
-	WIP             bool         `json:"wip"`
-	Comments        []Message    `json:"comments,omitempty"`
-	TrackingIDs     []TrackingID `json:"trackingIds"`
-	CurrentPatchSet PatchSet     `json:"currentPatchSet,omitEmpty"`
-	PatchSets       []PatchSet   `json:"patchsets,omitempty"`
-	DependsOn       Dependency   `json:"dependsOn,omitempty"`
-	NeededBy        Dependency   `json:"neededBy,omitempty"`
-	SubmitRecords   SubmitRecord
-	AllReviewers    []Account `json:"allReviewers,omitempty"`
+	Project         string         `json:"project"`
+	Branch          string         `json:"branch"`
+	ID              string         `json:"id"`
+	Number          int            `json:"number"`
+	Subject         string         `json:"subject"`
+	Owner           Account        `json:"owner"`
+	URL             string         `json:"url"`
+	CommitMessage   string         `json:"commitMessage"`
+	HashTags        []string       `json:"hashTags"`
+	CreatedOn       UnixTime       `json:"createdOn"`
+	LastUpdated     UnixTime       `json:"lastUpdated"`
+	Status          string         `json:"status"`
+	Open            bool           `json:"open"`
+	Private         bool           `json:"private"`
+	WIP             bool           `json:"wip"`
+	Comments        []Message      `json:"comments,omitempty"`
+	TrackingIDs     []TrackingID   `json:"trackingIds"`
+	CurrentPatchSet PatchSet       `json:"currentPatchSet,omitempty"`
+	PatchSets       []PatchSet     `json:"patchSets,omitempty"`
+	DependsOn       []Dependency   `json:"dependsOn,omitempty"`
+	NeededBy        []Dependency   `json:"neededBy,omitempty"`
+	SubmitRecords   []SubmitRecord `json:"submitRecords,omitempty"`
+	AllReviewers    []Account      `json:"allReviewers,omitempty"`
 }
 
 // SubmitRecord describes the submit status of a change.
 // https://gerrit-review.googlesource.com/Documentation/json.html#submitRecord
 type SubmitRecord struct {
-	Status string
-	Labels []Label
+	Status string  `json:"status"`
+	Labels []Label `json:"labels"`
 }
 
 // Label describes a code review label for a change.
 // https://gerrit-review.googlesource.com/Documentation/json.html#label
 type Label struct {
-	Label  string
-	Status string
-	By     Account
+	Label  string  `json:"label"`
+	Status string  `json:"status"`
+	By     Account `json:"by"`
 }
 
 // Message is a comment added on a Change by a reviewer.
@@ -136,11 +182,11 @@ type Message struct {
 // Dependency describes a change or patchset dependency.
 // https://gerrit-review.googlesource.com/Documentation/json.html#dependency
 type Dependency struct {
-	ID                string
-	Number            int
-	Revision          int
-	Ref               string
-	IsCurrentPatchSet bool
+	ID                string `json:"id"`
+	Number            int    `json:"number"`
+	Revision          string `json:"revision"`
+	Ref               string `json:"ref"`
+	IsCurrentPatchSet bool   `json:"isCurrentPatchSet"`
 }
 
 // Change status values.
@@ -166,6 +212,19 @@ type RefUpdate struct {
 	Project string `json:"project"` // Project path in Gerrit.
 }
 
+// ZeroRev is the revision used in a RefUpdate to indicate that the ref did
+// not exist before (OldRev) or after (NewRev) the update.
+const ZeroRev = "0000000000000000000000000000000000000000"
+
+// Branch returns the branch name of the updated ref, and true if the ref is
+// a branch (i.e. under refs/heads/).
+func (r RefUpdate) Branch() (string, bool) {
+	if !strings.HasPrefix(r.RefName, "refs/heads/") {
+		return "", false
+	}
+	return strings.TrimPrefix(r.RefName, "refs/heads/"), true
+}
+
 // UnmarshalEvent unmarshals a JSON-encoded Gerrit event.
 func UnmarshalEvent(b []byte) (*Event, error) {
 	x := struct {
@@ -255,6 +314,81 @@ type Event struct {
 	EventCreatedOn UnixTime `json:"eventCreatedOn"`
 }
 
+// ChangeNumber returns the number of the change associated with the event, and
+// false if the event is not associated with a change.
+func ChangeNumber(e *Event) (int, bool) {
+	c, ok := eventChange(e)
+	if !ok {
+		return 0, false
+	}
+	return c.Number, true
+}
+
+// eventChange returns the change associated with the event, and false if the
+// event is not associated with a change.
+func eventChange(e *Event) (*Change, bool) {
+	var c *Change
+	switch x := e.EventType.(type) {
+	case *AssigneeChanged:
+		c = &x.Change
+	case *ChangeAbandoned:
+		c = &x.Change
+	case *ChangeDeleted:
+		c = &x.Change
+	case *ChangeMerged:
+		c = &x.Change
+	case *ChangeRestored:
+		c = &x.Change
+	case *CommentAdded:
+		c = &x.Change
+	case *HashtagsChanged:
+		c = &x.Change
+	case *PatchsetCreated:
+		c = &x.Change
+	case *ReviewerAdded:
+		c = &x.Change
+	case *ReviewerDeleted:
+		c = &x.Change
+	case *TopicChanged:
+		c = &x.Change
+	case *WIPStateChanged:
+		c = &x.Change
+	case *PrivateStateChanged:
+		c = &x.Change
+	case *VoteDeleted:
+		c = &x.Change
+	default:
+		return nil, false
+	}
+	return c, true
+}
+
+// MarshalJSON implements json.Marshaler, encoding the event in the same form as
+// it is received from the stream (so that it can be decoded by UnmarshalEvent).
+func (e *Event) MarshalJSON() ([]byte, error) {
+	fields := make(map[string]interface{})
+	if u, ok := e.EventType.(*UnknownEventType); ok {
+		for k, v := range u.Data {
+			fields[k] = v
+		}
+	} else {
+		b, err := json.Marshal(e.EventType)
+		if err != nil {
+			return nil, err
+		}
+		var x map[string]json.RawMessage
+		if err := json.Unmarshal(b, &x); err != nil {
+			return nil, err
+		}
+		for k, v := range x {
+			fields[k] = v
+		}
+	}
+	fields["type"] = e.Type()
+	fields["eventCreatedOn"] = e.EventCreatedOn
+	return json.Marshal(fields)
+}
+
 // EventType is an interface that describes specific event types
 type EventType interface {
 	Type() string
@@ -263,12 +397,44 @@ type EventType interface {
 // Approval records the code review approval granted to a patch set.
 // https://gerrit-review.googlesource.com/Documentation/json.html#approval
 type Approval struct {
-	Type        string
-	Description string
-	Value       string
-	OldValue    string
-	GrantedOn   UnixTime
-	By          Account
+	Type        string   `json:"type"`
+	Description string   `json:"description"`
+	Value       string   `json:"value"`
+	OldValue    string   `json:"oldValue"`
+	GrantedOn   UnixTime `json:"grantedOn"`
+	By          Account  `json:"by"`
+}
+
+// IntValue returns the value of the approval as an int.  Values such as "+2",
+// " +2 " and "2" are all parsed as 2.
+func (a Approval) IntValue() (int, error) { return parseVote(a.Value) }
+
+// IntOldValue returns the old value of the approval as an int (see IntValue).
+func (a Approval) IntOldValue() (int, error) { return parseVote(a.OldValue) }
+
+// parseVote parses a vote value, ignoring surrounding whitespace.  A single
+// leading sign ('+' or '-') is permitted.
+func parseVote(s string) (int, error) {
+	return strconv.Atoi(strings.TrimSpace(s))
+}
+
+// MaxApproval returns the maximum value of the approvals of the given type
+// (e.g. "Code-Review") on the patchset, and false if there are none.
+func (p *PatchSet) MaxApproval(label string) (int, bool) {
+	max, ok := 0, false
+	for _, a := range p.Approvals {
+		if a.Type != label {
+			continue
+		}
+		v, err := a.IntValue()
+		if err != nil {
+			continue
+		}
+		if !ok || v > max {
+			max, ok = v, true
+		}
+	}
+	return max, ok
 }
 
 // Event.Type values.
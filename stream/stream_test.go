@@ -0,0 +1,256 @@
+package stream
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestUnmarshalEventPatchSet(t *testing.T) {
+	b := []byte(`{
+		"type": "comment-added",
+		"eventCreatedOn": 1600000100,
+		"change": {
+			"project": "gerrit",
+			"branch": "master",
+			"id": "I0123456789abcdef0123456789abcdef01234567",
+			"number": 12345,
+			"subject": "Fix the thing",
+			"owner": {"name": "Alice", "email": "alice@example.com", "username": "alice"},
+			"url": "https://gerrit.example.com/c/gerrit/+/12345",
+			"status": "NEW",
+			"open": true,
+			"currentPatchSet": {
+				"number": 2,
+				"revision": "89abcdef0123456789abcdef0123456789abcdef",
+				"parents": ["0123456789abcdef0123456789abcdef01234567"],
+				"ref": "refs/changes/45/12345/2",
+				"uploader": {"name": "Alice", "email": "alice@example.com", "username": "alice"},
+				"author": {"name": "Alice", "email": "alice@example.com", "username": "alice"},
+				"createdOn": 1600000000,
+				"kind": "REWORK",
+				"approvals": [
+					{"type": "Code-Review", "description": "Code-Review", "value": "2", "grantedOn": 1600000100, "by": {"name": "Bob", "username": "bob"}},
+					{"type": "Verified", "description": "Verified", "value": "-1", "grantedOn": 1600000050, "by": {"name": "CI", "username": "ci"}}
+				],
+				"comments": [
+					{"file": "main.go", "line": 10, "reviewer": {"name": "Bob", "username": "bob"}, "message": "Nit: typo"}
+				],
+				"files": [
+					{"file": "/COMMIT_MSG", "type": "ADDED", "insertions": 7, "deletions": 0},
+					{"file": "main.go", "fileOld": "old.go", "type": "RENAMED", "insertions": 3, "deletions": 1}
+				],
+				"sizeInsertions": 3,
+				"sizeDeletions": -1
+			}
+		},
+		"patchSet": {"number": 2, "revision": "89abcdef0123456789abcdef0123456789abcdef"},
+		"author": {"name": "Bob", "username": "bob"},
+		"comment": "Patch Set 2: Code-Review+2"
+	}`)
+
+	e, err := UnmarshalEvent(b)
+	if err != nil {
+		t.Fatalf("UnmarshalEvent() = %v", err)
+	}
+	ca, ok := e.EventType.(*CommentAdded)
+	if !ok {
+		t.Fatalf("EventType = %T, expected *CommentAdded", e.EventType)
+	}
+
+	ps := ca.Change.CurrentPatchSet
+	want := PatchSet{
+		Number:    2,
+		Revision:  "89abcdef0123456789abcdef0123456789abcdef",
+		Parents:   []string{"0123456789abcdef0123456789abcdef01234567"},
+		Ref:       "refs/changes/45/12345/2",
+		Uploader:  Account{Name: "Alice", Email: "alice@example.com", Username: "alice"},
+		Author:    Account{Name: "Alice", Email: "alice@example.com", Username: "alice"},
+		CreatedOn: UnixTime(time.Unix(1600000000, 0)),
+		Kind:      "REWORK",
+		Approvals: []Approval{
+			{Type: "Code-Review", Description: "Code-Review", Value: "2", GrantedOn: UnixTime(time.Unix(1600000100, 0)), By: Account{Name: "Bob", Username: "bob"}},
+			{Type: "Verified", Description: "Verified", Value: "-1", GrantedOn: UnixTime(time.Unix(1600000050, 0)), By: Account{Name: "CI", Username: "ci"}},
+		},
+		Comments: []PatchsetComment{
+			{File: "main.go", Line: 10, Reviewer: Account{Name: "Bob", Username: "bob"}, Message: "Nit: typo"},
+		},
+		Files: []File{
+			{File: "/COMMIT_MSG", Type: FileTypeAdded, Insertions: 7},
+			{File: "main.go", FileOld: "old.go", Type: FileTypeRenamed, Insertions: 3, Deletions: 1},
+		},
+		SizeInsertions: 3,
+		SizeDeletions:  -1,
+	}
+	if !reflect.DeepEqual(ps, want) {
+		t.Errorf("CurrentPatchSet = %+v, expected %+v", ps, want)
+	}
+
+	if v, ok := ps.MaxApproval("Code-Review"); !ok || v != 2 {
+		t.Errorf("MaxApproval(Code-Review) = %d, %v, expected 2, true", v, ok)
+	}
+	if ca.Change.Number != 12345 || ca.PatchSet.Number != 2 {
+		t.Errorf("got change %d patchset %d, expected change 12345 patchset 2", ca.Change.Number, ca.PatchSet.Number)
+	}
+}
+
+func TestUnmarshalDependsOn(t *testing.T) {
+	b := []byte(`{
+		"type": "patchset-created",
+		"change": {
+			"number": 2,
+			"dependsOn": [
+				{
+					"id": "I0000000000000000000000000000000000000001",
+					"number": 1,
+					"revision": "4c4c5b6ec8a1b7e1d4f5c1b3d4b2a4c1e0f9d8c7",
+					"ref": "refs/changes/01/1/3",
+					"isCurrentPatchSet": true
+				}
+			],
+			"neededBy": [
+				{
+					"id": "I0000000000000000000000000000000000000003",
+					"number": 3,
+					"revision": "00e1d2c3b4a5968778695a4b3c2d1e0f0a1b2c3d",
+					"ref": "refs/changes/03/3/1",
+					"isCurrentPatchSet": false
+				}
+			]
+		}
+	}`)
+
+	e, err := UnmarshalEvent(b)
+	if err != nil {
+		t.Fatalf("UnmarshalEvent() = %v", err)
+	}
+	c := e.EventType.(*PatchsetCreated).Change
+
+	wantDependsOn := []Dependency{{
+		ID:                "I0000000000000000000000000000000000000001",
+		Number:            1,
+		Revision:          "4c4c5b6ec8a1b7e1d4f5c1b3d4b2a4c1e0f9d8c7",
+		Ref:               "refs/changes/01/1/3",
+		IsCurrentPatchSet: true,
+	}}
+	if !reflect.DeepEqual(c.DependsOn, wantDependsOn) {
+		t.Errorf("DependsOn = %+v, expected %+v", c.DependsOn, wantDependsOn)
+	}
+
+	wantNeededBy := []Dependency{{
+		ID:       "I0000000000000000000000000000000000000003",
+		Number:   3,
+		Revision: "00e1d2c3b4a5968778695a4b3c2d1e0f0a1b2c3d",
+		Ref:      "refs/changes/03/3/1",
+	}}
+	if !reflect.DeepEqual(c.NeededBy, wantNeededBy) {
+		t.Errorf("NeededBy = %+v, expected %+v", c.NeededBy, wantNeededBy)
+	}
+}
+
+func TestUnixTimeUnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		in   string
+		want time.Time
+	}{
+		{`1600000000`, time.Unix(1600000000, 0)},
+		{`"1600000000"`, time.Unix(1600000000, 0)},
+		{`1600000000.75`, time.Unix(1600000000, 0)},
+		{`"1600000000.75"`, time.Unix(1600000000, 0)},
+		{`4102444800`, time.Unix(4102444800, 0)}, // 2100-01-01, after the 32-bit overflow in 2038.
+		{`0`, time.Time{}},
+	}
+
+	for _, tt := range tests {
+		var ut UnixTime
+		if err := ut.UnmarshalJSON([]byte(tt.in)); err != nil {
+			t.Errorf("UnmarshalJSON(%s) = %v", tt.in, err)
+			continue
+		}
+		if got := ut.Time(); !got.Equal(tt.want) {
+			t.Errorf("UnmarshalJSON(%s) = %v, expected %v", tt.in, got, tt.want)
+		}
+	}
+
+	for _, in := range []string{`"abc"`, `true`, `{}`} {
+		var ut UnixTime
+		if err := ut.UnmarshalJSON([]byte(in)); err == nil {
+			t.Errorf("UnmarshalJSON(%s) = nil, expected error", in)
+		}
+	}
+}
+
+func TestParseVote(t *testing.T) {
+	tests := []struct {
+		in   string
+		want int
+	}{
+		{"2", 2},
+		{"+2", 2},
+		{"-2", -2},
+		{"0", 0},
+		{" +1 ", 1},
+		{"\t-1\n", -1},
+		{" 0", 0},
+	}
+
+	for _, tt := range tests {
+		got, err := parseVote(tt.in)
+		if err != nil {
+			t.Errorf("parseVote(%q) = %v", tt.in, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("parseVote(%q) = %d, expected %d", tt.in, got, tt.want)
+		}
+	}
+
+	for _, in := range []string{"", "+", "++1", "+-1", "1.5"} {
+		if _, err := parseVote(in); err == nil {
+			t.Errorf("parseVote(%q) = nil error, expected error", in)
+		}
+	}
+}
+
+func TestEventMarshalJSONRoundTrip(t *testing.T) {
+	tests := []string{
+		`{
+			"type": "comment-added",
+			"eventCreatedOn": 1600000100,
+			"change": {"project": "gerrit", "branch": "master", "number": 12345, "subject": "Fix the thing", "owner": {"username": "alice"}},
+			"patchSet": {"number": 2, "revision": "89abcdef0123456789abcdef0123456789abcdef", "createdOn": 1600000000},
+			"author": {"name": "Bob", "username": "bob"},
+			"approvals": [{"type": "Code-Review", "description": "Code-Review", "value": "2", "oldValue": "0"}],
+			"comment": "Patch Set 2: Code-Review+2"
+		}`,
+		`{
+			"type": "ref-updated",
+			"eventCreatedOn": 1600000200,
+			"submitter": {"username": "alice"},
+			"refUpdate": {"oldRev": "0123456789abcdef0123456789abcdef01234567", "newRev": "89abcdef0123456789abcdef0123456789abcdef", "refName": "refs/heads/master", "project": "gerrit"}
+		}`,
+		`{
+			"type": "custom-event",
+			"eventCreatedOn": 1600000300,
+			"payload": {"key": "value", "count": 3}
+		}`,
+	}
+
+	for _, in := range tests {
+		e, err := UnmarshalEvent([]byte(in))
+		if err != nil {
+			t.Fatalf("UnmarshalEvent() = %v", err)
+		}
+		b, err := e.MarshalJSON()
+		if err != nil {
+			t.Fatalf("%v: MarshalJSON() = %v", e.Type(), err)
+		}
+		got, err := UnmarshalEvent(b)
+		if err != nil {
+			t.Fatalf("%v: UnmarshalEvent(MarshalJSON()) = %v", e.Type(), err)
+		}
+		if !reflect.DeepEqual(got, e) {
+			t.Errorf("%v: UnmarshalEvent(MarshalJSON()) = %+v, expected %+v", e.Type(), got, e)
+		}
+	}
+}
@@ -78,6 +78,27 @@ type CommentAdded struct {
 // Type of the event.
 func (CommentAdded) Type() string { return EventTypeCommentAdded }
 
+// ChangedApprovals returns the approvals whose value was changed by the comment.
+// Gerrit only sets OldValue on approvals which were changed.
+func (c CommentAdded) ChangedApprovals() []Approval {
+	var out []Approval
+	for _, a := range c.Approvals {
+		if a.OldValue == "" {
+			continue
+		}
+		v, err := a.IntValue()
+		ov, oerr := a.IntOldValue()
+		if err == nil && oerr == nil && v == ov {
+			continue
+		}
+		if a.Value == a.OldValue {
+			continue
+		}
+		out = append(out, a)
+	}
+	return out
+}
+
 type DroppedOutput struct {
 }
 
@@ -120,6 +141,12 @@ type RefUpdated struct {
 // Type of the event.
 func (RefUpdated) Type() string { return EventTypeRefUpdated }
 
+// IsDeletion returns true if the ref was deleted by the update.
+func (r RefUpdated) IsDeletion() bool { return r.RefUpdate.NewRev == ZeroRev }
+
+// IsCreation returns true if the ref was created by the update.
+func (r RefUpdated) IsCreation() bool { return r.RefUpdate.OldRev == ZeroRev }
+
 type ReviewerAdded struct {
 	Change   Change   `json:"change,omitempty"`   // Change associated with the event.
 	PatchSet PatchSet `json:"patchSet,omitempty"` // PatchSet (of the Change) associated with the event.
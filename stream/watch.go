@@ -0,0 +1,33 @@
+package stream
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrStreamClosed is returned by WatchChange when the event channel is closed
+// before a matching event is received.
+var ErrStreamClosed = errors.New("stream closed")
+
+// WatchChange reads events until one associated with the change changeNumber
+// satisfies pred, and returns it.  If pred is nil then the first event
+// associated with the change is returned.
+func WatchChange(ctx context.Context, events <-chan *Event, changeNumber int, pred func(*Event) bool) (*Event, error) {
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+
+		case e, ok := <-events:
+			if !ok {
+				return nil, ErrStreamClosed
+			}
+			if n, ok := ChangeNumber(e); !ok || n != changeNumber {
+				continue
+			}
+			if pred == nil || pred(e) {
+				return e, nil
+			}
+		}
+	}
+}
@@ -5,16 +5,24 @@ import (
 	"fmt"
 	"sort"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/dhowden/gerrit"
+	"github.com/dhowden/gerrit/internal/parallel"
 )
 
 // Summary of a change.
 type Summary struct {
+	// BaseURL is the base URL of the Gerrit web UI, used to construct absolute
+	// thread URLs.
+	BaseURL string
+
 	ChangeID string
 	Project  string
 	Branch   string
+	Topic    string
+	Hashtags []string
 
 	Subject string
 
@@ -42,22 +50,190 @@ type Thread struct {
 	PatchSet int
 	Authors  []gerrit.AccountInfo
 	Message  string
+	Resolved bool // Only true if resolved threads were requested (see SummariseOptions).
+
+	PatchSetLevel bool // True if the thread is a patchset level comment (not attached to a file).
 
 	LastComment gerrit.CommentInfo
 }
 
+// URL returns the URL of the thread in the Gerrit web UI.  The URL is absolute if
+// the BaseURL of the summary is set.
 func (t *Thread) URL() string {
-	return fmt.Sprintf("/c/%s/+/%s/%d/%v#%d", t.s.Project, t.s.ChangeID, t.PatchSet, t.Path, t.Line)
+	return t.URLWithBase(t.s.BaseURL)
+}
+
+// URLWithBase returns the URL of the thread in the Gerrit web UI at base.
+func (t *Thread) URLWithBase(base string) string {
+	path := t.Path
+	if t.PatchSetLevel {
+		path = PatchSetLevelPath
+	}
+	return gerrit.CommentURL(base, t.s.Project, t.s.ChangeID, t.PatchSet, path, t.Line)
+}
+
+// WhoShouldRespond returns the (deduplicated) authors of the latest comment in each
+// unresolved thread, i.e. the accounts which are waiting on a reply.
+func (s *Summary) WhoShouldRespond() []gerrit.AccountInfo {
+	var out []gerrit.AccountInfo
+	dedup := make(map[string]bool)
+	for _, t := range s.Threads {
+		if t.Resolved {
+			continue
+		}
+		a := t.LastComment.Author
+		if dedup[a.Username] {
+			continue
+		}
+		dedup[a.Username] = true
+		out = append(out, a)
+	}
+	return out
+}
+
+// changeOptions are the options used when fetching changes to summarise.
+var changeOptions = []gerrit.ChangeOption{
+	gerrit.OptionMessages,
+	gerrit.OptionDetailedLabels,
+	gerrit.OptionCurrentRevision,
+	gerrit.OptionCurrentCommit,
+	gerrit.OptionDetailedAccounts,
+}
+
+// SummariseOptions are options for SummariseWithOptions.
+type SummariseOptions struct {
+	// IncludeResolved includes resolved threads in the summary (by default only
+	// unresolved threads are included).
+	IncludeResolved bool
+
+	// SortBy determines the order of threads (defaults to SortByUpdated).
+	SortBy SortBy
+
+	// BaseURL overrides the base URL used for thread URLs (see Summary.BaseURL),
+	// for instances where the web UI is served from a different host to the API.
+	// Defaults to the web UI base of the client (see gerrit.Client.WebBase).
+	BaseURL string
+}
+
+// SortBy is a thread ordering.
+type SortBy int
+
+// SortBy values.
+const (
+	SortByUpdated  SortBy = iota // Order threads by the time of the latest comment (oldest first).
+	SortByLocation               // Order threads by path and then line, with patchset level and commit message threads first.
+)
+
+// Magic file paths used by Gerrit.
+const (
+	PatchSetLevelPath = gerrit.PatchSetLevelPath
+	CommitMsgPath     = gerrit.CommitMsgPath
+)
+
+// pathRank returns the rank used to order special paths before others.
+func pathRank(path string) int {
+	switch path {
+	case PatchSetLevelPath:
+		return 0
+	case CommitMsgPath:
+		return 1
+	}
+	return 2
+}
+
+// defaultConcurrency is the default maximum number of concurrent requests made
+// by SummariseMany.
+const defaultConcurrency = 8
+
+// ManyOptions are options for SummariseMany.
+type ManyOptions struct {
+	SummariseOptions
+
+	// Concurrency is the maximum number of concurrent requests.  Defaults to 8.
+	Concurrency int
+
+	// Options are additional options to request when querying changes.
+	Options []gerrit.ChangeOption
 }
 
 // Summarise the comment threads into unresolved items.
 func Summarise(ctx context.Context, gc *gerrit.Client, changeID string) (*Summary, error) {
+	return SummariseWithOptions(ctx, gc, changeID, nil)
+}
+
+// SummariseWithOptions is like Summarise, but with options to control which
+// threads are included.
+func SummariseWithOptions(ctx context.Context, gc *gerrit.Client, changeID string, opts *SummariseOptions) (*Summary, error) {
+	if opts == nil {
+		opts = &SummariseOptions{}
+	}
 	gcc := &gerrit.ChangesClient{Client: gc}
 
-	ch, err := gcc.GetChange(ctx, changeID, "MESSAGES", "DETAILED_LABELS", "CURRENT_REVISION", "CURRENT_COMMIT", "DETAILED_ACCOUNTS")
+	ch, err := gcc.GetChange(ctx, changeID, changeOptions...)
 	if err != nil {
 		return nil, fmt.Errorf("could not get change: %w", err)
 	}
+	return summarise(ctx, gcc, changeID, ch, opts)
+}
+
+// SummariseQuery summarises the comment threads of all changes matching the query.
+// Changes are fetched with a single query, and then comments are fetched for each
+// change concurrently.
+func SummariseQuery(ctx context.Context, gc *gerrit.Client, query string) ([]*Summary, error) {
+	return SummariseMany(ctx, gc, query, nil)
+}
+
+// SummariseProject summarises the comment threads of all open changes in a project.
+func SummariseProject(ctx context.Context, gc *gerrit.Client, project string, opts *ManyOptions) ([]*Summary, error) {
+	return SummariseMany(ctx, gc, fmt.Sprintf("project:%q status:open", project), opts)
+}
+
+// SummariseMany is like SummariseQuery, but with options to control the concurrency
+// and the options used when querying changes.
+func SummariseMany(ctx context.Context, gc *gerrit.Client, query string, opts *ManyOptions) ([]*Summary, error) {
+	if opts == nil {
+		opts = &ManyOptions{}
+	}
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = defaultConcurrency
+	}
+	chOpts := append(append([]gerrit.ChangeOption(nil), changeOptions...), opts.Options...)
+
+	gcc := &gerrit.ChangesClient{Client: gc}
+
+	chs, err := gcc.QueryChanges(ctx, query, chOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("could not query changes: %w", err)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	out := make([]*Summary, len(chs))
+	errs := parallel.Do(ctx, len(chs), concurrency, func(i int) error {
+		ch := &chs[i]
+		var err error
+		out[i], err = summarise(ctx, gcc, strconv.Itoa(ch.Number), ch, &opts.SummariseOptions)
+		if err != nil {
+			cancel()
+		}
+		return err
+	})
+	for i, err := range errs {
+		if err != nil {
+			return nil, fmt.Errorf("could not summarise change %d: %w", chs[i].Number, err)
+		}
+	}
+	return out, nil
+}
+
+func summarise(ctx context.Context, gcc *gerrit.ChangesClient, changeID string, ch *gerrit.ChangeInfo, opts *SummariseOptions) (*Summary, error) {
+	baseURL := opts.BaseURL
+	if baseURL == "" {
+		baseURL = gcc.Client.WebBase()
+	}
+	baseURL = strings.TrimSuffix(baseURL, "/")
 
 	// Extract commit message
 	commitMessage := ""
@@ -80,11 +256,14 @@ func Summarise(ctx context.Context, gc *gerrit.Client, changeID string) (*Summar
 		activeReviewersDedup[m.Author.Username] = true
 	}
 
-	if ch.UnresolvedCommentCount == 0 {
+	if ch.UnresolvedCommentCount == 0 && (!opts.IncludeResolved || ch.TotalCommentCount == 0) {
 		return &Summary{
+			BaseURL:             baseURL,
 			ChangeID:            strconv.Itoa(ch.Number),
 			Project:             ch.Project,
 			Branch:              ch.Branch,
+			Topic:               ch.Topic,
+			Hashtags:            ch.Hashtags,
 			Subject:             ch.Subject,
 			LatestCommitMessage: commitMessage,
 			Created:             ch.Created.Time(),
@@ -122,8 +301,8 @@ func Summarise(ctx context.Context, gc *gerrit.Client, changeID string) (*Summar
 			as = append(as, c.Author)
 			authors[c.ID] = as
 
-			// Only record unresolved comments...
-			if c.Unresolved == true {
+			// Only record unresolved comments (unless resolved are included)...
+			if c.Unresolved || opts.IncludeResolved {
 				threads[c.ID] = c
 			}
 		}
@@ -134,9 +313,27 @@ func Summarise(ctx context.Context, gc *gerrit.Client, changeID string) (*Summar
 		ucs = append(ucs, c)
 	}
 
-	sort.Slice(ucs, func(i, j int) bool {
-		return ucs[i].Updated.Time().Before(ucs[j].Updated.Time())
-	})
+	switch opts.SortBy {
+	case SortByLocation:
+		sort.Slice(ucs, func(i, j int) bool {
+			a, b := ucs[i], ucs[j]
+			if ra, rb := pathRank(a.Path), pathRank(b.Path); ra != rb {
+				return ra < rb
+			}
+			if a.Path != b.Path {
+				return a.Path < b.Path
+			}
+			if a.Line != b.Line {
+				return a.Line < b.Line
+			}
+			return a.Updated.Time().Before(b.Updated.Time())
+		})
+
+	default:
+		sort.Slice(ucs, func(i, j int) bool {
+			return ucs[i].Updated.Time().Before(ucs[j].Updated.Time())
+		})
+	}
 
 	for k, as := range authors {
 		dedup := make(map[string]struct{})
@@ -152,9 +349,12 @@ func Summarise(ctx context.Context, gc *gerrit.Client, changeID string) (*Summar
 	}
 
 	s := &Summary{
+		BaseURL:             baseURL,
 		ChangeID:            strconv.Itoa(ch.Number),
 		Project:             ch.Project,
 		Branch:              ch.Branch,
+		Topic:               ch.Topic,
+		Hashtags:            ch.Hashtags,
 		Subject:             ch.Subject,
 		LatestCommitMessage: commitMessage,
 		Created:             ch.Created.Time(),
@@ -170,13 +370,15 @@ func Summarise(ctx context.Context, gc *gerrit.Client, changeID string) (*Summar
 
 	for _, uc := range ucs {
 		s.Threads = append(s.Threads, Thread{
-			s:           s,
-			Path:        uc.Path,
-			Line:        uc.Line,
-			PatchSet:    uc.PatchSet,
-			Authors:     authors[uc.ID],
-			Message:     uc.Message,
-			LastComment: uc,
+			s:             s,
+			Path:          uc.Path,
+			Line:          uc.Line,
+			PatchSet:      uc.PatchSet,
+			Authors:       authors[uc.ID],
+			Message:       uc.Message,
+			Resolved:      !uc.Unresolved,
+			PatchSetLevel: uc.Path == PatchSetLevelPath,
+			LastComment:   uc,
 		})
 	}
 	return s, nil
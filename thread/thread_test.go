@@ -0,0 +1,230 @@
+package thread
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/dhowden/gerrit"
+)
+
+// newTestClient returns a client for a server which responds to requests for each
+// path in responses (excluding the /a prefix and query string) with the given JSON.
+// The returned func closes the server.
+func newTestClient(t *testing.T, responses map[string]string) (*gerrit.Client, func()) {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, ok := responses[r.URL.Path[len("/a"):]]
+		if !ok {
+			t.Errorf("unexpected request: %v", r.URL)
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprint(w, ")]}'\n"+body)
+	}))
+	return gerrit.NewClient(srv.URL, "user", "pass"), srv.Close
+}
+
+func usernames(as []gerrit.AccountInfo) []string {
+	out := make([]string, 0, len(as))
+	for _, a := range as {
+		out = append(out, a.Username)
+	}
+	return out
+}
+
+func TestWhoShouldRespondIncludeResolved(t *testing.T) {
+	gc, done := newTestClient(t, map[string]string{
+		"/changes/1": `{
+			"project": "p",
+			"_number": 1,
+			"owner": {"_account_id": 1, "username": "owner"},
+			"unresolved_comment_count": 1,
+			"total_comment_count": 2
+		}`,
+		"/changes/1/comments": `{
+			"a.go": [
+				{"id": "c1", "line": 1, "patch_set": 1, "author": {"username": "alice"}, "unresolved": true, "updated": "2020-01-01 00:00:00.000000000"},
+				{"id": "c2", "line": 2, "patch_set": 1, "author": {"username": "bob"}, "unresolved": false, "updated": "2020-01-02 00:00:00.000000000"}
+			]
+		}`,
+	})
+	defer done()
+
+	s, err := SummariseWithOptions(context.Background(), gc, "1", &SummariseOptions{IncludeResolved: true})
+	if err != nil {
+		t.Fatalf("SummariseWithOptions() = %v", err)
+	}
+	if len(s.Threads) != 2 {
+		t.Fatalf("got %d threads, expected 2", len(s.Threads))
+	}
+
+	got := usernames(s.WhoShouldRespond())
+	if len(got) != 1 || got[0] != "alice" {
+		t.Errorf("WhoShouldRespond() = %v, expected [alice]", got)
+	}
+}
+
+func TestSummariseTopicHashtags(t *testing.T) {
+	gc, done := newTestClient(t, map[string]string{
+		"/changes/1": `{
+			"project": "p",
+			"branch": "master",
+			"_number": 1,
+			"topic": "release-1.0",
+			"hashtags": ["security", "backport"],
+			"owner": {"_account_id": 1, "username": "owner"}
+		}`,
+	})
+	defer done()
+
+	s, err := Summarise(context.Background(), gc, "1")
+	if err != nil {
+		t.Fatalf("Summarise() = %v", err)
+	}
+	if s.Topic != "release-1.0" {
+		t.Errorf("Topic = %q, expected release-1.0", s.Topic)
+	}
+	if want := []string{"security", "backport"}; !reflect.DeepEqual(s.Hashtags, want) {
+		t.Errorf("Hashtags = %v, expected %v", s.Hashtags, want)
+	}
+}
+
+func TestWhoShouldRespondOwnerAndReviewer(t *testing.T) {
+	gc, done := newTestClient(t, map[string]string{
+		"/changes/1": `{
+			"project": "p",
+			"_number": 1,
+			"owner": {"_account_id": 1, "username": "owner"},
+			"unresolved_comment_count": 3,
+			"total_comment_count": 3
+		}`,
+		"/changes/1/comments": `{
+			"a.go": [
+				{"id": "c1", "line": 1, "patch_set": 1, "author": {"_account_id": 1, "username": "owner"}, "unresolved": true, "updated": "2020-01-01 00:00:00.000000000"},
+				{"id": "c2", "line": 2, "patch_set": 1, "author": {"_account_id": 2, "username": "alice"}, "unresolved": true, "updated": "2020-01-02 00:00:00.000000000"},
+				{"id": "c3", "line": 3, "patch_set": 1, "author": {"_account_id": 1, "username": "owner"}, "unresolved": true, "updated": "2020-01-03 00:00:00.000000000"}
+			]
+		}`,
+	})
+	defer done()
+
+	s, err := Summarise(context.Background(), gc, "1")
+	if err != nil {
+		t.Fatalf("Summarise() = %v", err)
+	}
+	if len(s.Threads) != 3 {
+		t.Fatalf("got %d threads, expected 3", len(s.Threads))
+	}
+
+	got := usernames(s.WhoShouldRespond())
+	if want := []string{"owner", "alice"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("WhoShouldRespond() = %v, expected %v", got, want)
+	}
+}
+
+func TestSummariseProject(t *testing.T) {
+	gc, done := newTestClient(t, map[string]string{
+		"/changes/": `[
+			{"project": "p", "branch": "master", "_number": 1, "subject": "First"},
+			{"project": "p", "branch": "master", "_number": 2, "subject": "Second", "unresolved_comment_count": 1, "total_comment_count": 1}
+		]`,
+		"/changes/2/comments": `{
+			"a.go": [
+				{"id": "c1", "line": 1, "patch_set": 1, "author": {"username": "alice"}, "unresolved": true, "updated": "2020-01-01 00:00:00.000000000"}
+			]
+		}`,
+	})
+	defer done()
+
+	ss, err := SummariseProject(context.Background(), gc, "p", nil)
+	if err != nil {
+		t.Fatalf("SummariseProject() = %v", err)
+	}
+	if len(ss) != 2 {
+		t.Fatalf("got %d summaries, expected 2", len(ss))
+	}
+	for i, want := range []struct {
+		changeID, subject string
+		threads           int
+	}{
+		{"1", "First", 0},
+		{"2", "Second", 1},
+	} {
+		s := ss[i]
+		if s.ChangeID != want.changeID || s.Subject != want.subject || len(s.Threads) != want.threads {
+			t.Errorf("summary %d = {%q, %q, %d threads}, expected {%q, %q, %d threads}", i, s.ChangeID, s.Subject, len(s.Threads), want.changeID, want.subject, want.threads)
+		}
+	}
+}
+
+func TestPatchSetLevelThread(t *testing.T) {
+	gc, done := newTestClient(t, map[string]string{
+		"/changes/1": `{
+			"project": "p",
+			"_number": 1,
+			"unresolved_comment_count": 1,
+			"total_comment_count": 1
+		}`,
+		"/changes/1/comments": `{
+			"/PATCHSET_LEVEL": [
+				{"id": "c1", "patch_set": 2, "author": {"username": "alice"}, "message": "LGTM apart from the naming", "unresolved": true, "updated": "2020-01-01 00:00:00.000000000"}
+			]
+		}`,
+	})
+	defer done()
+
+	s, err := Summarise(context.Background(), gc, "1")
+	if err != nil {
+		t.Fatalf("Summarise() = %v", err)
+	}
+	if len(s.Threads) != 1 {
+		t.Fatalf("got %d threads, expected 1", len(s.Threads))
+	}
+	th := s.Threads[0]
+	if !th.PatchSetLevel {
+		t.Errorf("PatchSetLevel = false, expected true")
+	}
+	if got, want := th.URLWithBase("https://review.example.com"), "https://review.example.com/c/p/+/1/2"; got != want {
+		t.Errorf("URLWithBase() = %q, expected %q", got, want)
+	}
+}
+
+func TestThreadURL(t *testing.T) {
+	gc, done := newTestClient(t, map[string]string{
+		"/changes/1": `{
+			"project": "p",
+			"_number": 1,
+			"unresolved_comment_count": 1,
+			"total_comment_count": 1
+		}`,
+		"/changes/1/comments": `{
+			"a.go": [
+				{"id": "c1", "line": 7, "patch_set": 1, "author": {"username": "alice"}, "unresolved": true, "updated": "2020-01-01 00:00:00.000000000"}
+			]
+		}`,
+	})
+	defer done()
+
+	tests := []struct {
+		baseURL, want string
+	}{
+		{"", gc.Root() + "/c/p/+/1/1/a.go#7"},
+		{"https://review.example.com/", "https://review.example.com/c/p/+/1/1/a.go#7"},
+	}
+	for _, tt := range tests {
+		s, err := SummariseWithOptions(context.Background(), gc, "1", &SummariseOptions{BaseURL: tt.baseURL})
+		if err != nil {
+			t.Fatalf("SummariseWithOptions() = %v", err)
+		}
+		if len(s.Threads) != 1 {
+			t.Fatalf("got %d threads, expected 1", len(s.Threads))
+		}
+		if got := s.Threads[0].URL(); got != tt.want {
+			t.Errorf("URL() with BaseURL %q = %q, expected %q", tt.baseURL, got, tt.want)
+		}
+	}
+}